@@ -0,0 +1,23 @@
+// Package access is used for testing purpose only
+package access
+
+//go:generate cmtstringer -type Access -sort value -values -guard -int
+
+// Access type of flag-style constant with shifted values
+type Access uint8
+
+const (
+	// AccessExecute Execute
+	AccessExecute Access = 1 << iota
+	// AccessWrite Write
+	AccessWrite
+	// AccessRead Read
+	AccessRead
+)
+
+const (
+	// AccessNone No access
+	AccessNone Access = 0
+	// AccessAll Full access
+	AccessAll = AccessRead | AccessWrite | AccessExecute
+)
@@ -0,0 +1,40 @@
+package access
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAccess(t *testing.T) {
+	data := map[Access]string{
+		AccessNone:    "No access",
+		AccessExecute: "Execute",
+		AccessWrite:   "Write",
+		AccessRead:    "Read",
+		AccessAll:     "Full access",
+		3:             "Unknown",
+	}
+
+	for access, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, access.String(), msg)
+		})
+	}
+}
+
+func TestAccessValues(t *testing.T) {
+	// Constants are sorted by their computed values.
+	assertEqual(t, fmt.Sprint(AccessValues()), "[No access Execute Write Read Full access]")
+}
+
+func TestAccessInt(t *testing.T) {
+	if AccessAll.Int() != 7 {
+		t.Fatalf("Access value is incorrect\nExpected: 7\nObtained: %d", AccessAll.Int())
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Access message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
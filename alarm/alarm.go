@@ -0,0 +1,20 @@
+// Package alarm is used for testing purpose only
+package alarm
+
+//go:generate cmtstringer -type Alarm
+
+// Alarm type of constant with comments separating the name by a colon or a dash
+type Alarm int
+
+const (
+	// AlarmFire: Fire alarm
+	AlarmFire Alarm = iota
+	// AlarmFlood - Flood alarm
+	AlarmFlood
+	// AlarmGas — Gas leak
+	AlarmGas
+	// AlarmFrost -40 degrees reached
+	AlarmFrost
+	// AlarmDrill Fire drill
+	AlarmDrill
+)
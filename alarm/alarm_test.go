@@ -0,0 +1,25 @@
+package alarm
+
+import "testing"
+
+func TestAlarm(t *testing.T) {
+	data := map[Alarm]string{
+		AlarmFire:  "Fire alarm",
+		AlarmFlood: "Flood alarm",
+		AlarmGas:   "Gas leak",
+		AlarmFrost: "-40 degrees reached",
+		AlarmDrill: "Fire drill",
+	}
+
+	for alarm, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, alarm.String(), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Alarm message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
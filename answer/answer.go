@@ -0,0 +1,32 @@
+// Package answer is used for testing purpose only
+package answer
+
+//go:generate cmtstringer -type Answer -no-name-prefix -registry
+
+// Answer type of constant documented without repeating its name
+type Answer int
+
+const (
+	// Affirmative answer
+	Yes Answer = iota + 1
+	// Negative answer
+	No
+	// Maybe Undecided answer
+	Maybe
+)
+
+// Confidence type unrelated to Answer, declared in the same const block
+type Confidence int
+
+const (
+	// Agree Agreeing answer
+	Agree Answer = iota + 10
+	// Certain Certain confidence
+	Certain Confidence = iota + 4
+	// Unsure Unsure confidence
+	Unsure
+	// Disagree Disagreeing answer
+	Disagree Answer = iota + 10
+	// Neutral Neither agreeing nor disagreeing answer
+	Neutral
+)
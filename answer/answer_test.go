@@ -0,0 +1,68 @@
+package answer
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAnswerMessage(t *testing.T) {
+	data := map[Answer]string{
+		Yes:   "Affirmative answer",
+		No:    "Negative answer",
+		Maybe: "Undecided answer",
+	}
+
+	for answer, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", answer), msg)
+		})
+	}
+}
+
+func TestMixedAnswerMessage(t *testing.T) {
+	data := map[Answer]string{
+		Agree:    "Agreeing answer",
+		Disagree: "Disagreeing answer",
+		Neutral:  "Neither agreeing nor disagreeing answer",
+	}
+
+	for answer, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", answer), msg)
+		})
+	}
+
+	// Constants of the other type in the block aren't taken.
+	for _, confidence := range []Confidence{Certain, Unsure} {
+		if msg, ok := AnswerByValue(Answer(confidence)); ok {
+			t.Fatalf("Unexpected answer %q of confidence %d", msg, confidence)
+		}
+	}
+}
+
+func TestAnswerRegistry(t *testing.T) {
+	answer, ok := AnswerByName("Negative answer")
+	if !ok {
+		t.Fatal("Expected answer to be found by name")
+	}
+	assertEqual(t, answer.String(), No.String())
+
+	msg, ok := AnswerByValue(Maybe)
+	if !ok {
+		t.Fatal("Expected answer to be found by value")
+	}
+	assertEqual(t, msg, "Undecided answer")
+
+	if _, ok := AnswerByName("Unknown"); ok {
+		t.Fatal("Expected unknown name not to be found")
+	}
+	if _, ok := AnswerByValue(0); ok {
+		t.Fatal("Expected unknown value not to be found")
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Answer message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
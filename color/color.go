@@ -0,0 +1,16 @@
+// Package color is used for testing purpose only
+package color
+
+//go:generate cmtstringer -type Color
+
+// Color type of string-based color constant
+type Color string
+
+const (
+	// Red Fire Engine Red
+	Red Color = "red"
+	// Green Forest Green
+	Green Color = "green"
+	// Blue Navy Blue
+	Blue Color = "blue"
+)
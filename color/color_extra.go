@@ -0,0 +1,8 @@
+//go:build cmtstringer_extra
+
+package color
+
+const (
+	// Purple Royal Purple
+	Purple Color = "purple"
+)
@@ -0,0 +1,30 @@
+package color
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestColorMessage(t *testing.T) {
+	data := map[Color]string{
+		Red:   "Fire Engine Red",
+		Green: "Forest Green",
+		Blue:  "Navy Blue",
+	}
+
+	for color, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", color), msg)
+		})
+	}
+}
+
+func TestUnknownColorMessage(t *testing.T) {
+	assertEqual(t, fmt.Sprintf("%v", Color("purple")), "Unknown")
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Color message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,16 @@
+// Package currency is used for testing purpose only
+package currency
+
+//go:generate cmtstringer -type Currency -trimprefix Currency -description
+
+// Currency type of constant having both a code and a name
+type Currency int
+
+const (
+	// CurrencyUSD United States dollar
+	CurrencyUSD Currency = iota + 1
+	// CurrencyEUR Euro
+	CurrencyEUR
+	// CurrencyJPY Japanese yen
+	CurrencyJPY
+)
@@ -0,0 +1,42 @@
+package currency
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCurrencyMessage(t *testing.T) {
+	data := map[Currency]string{
+		CurrencyUSD: "USD",
+		CurrencyEUR: "EUR",
+		CurrencyJPY: "JPY",
+		0:           "Unknown",
+	}
+
+	for currency, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", currency), msg)
+		})
+	}
+}
+
+func TestCurrencyDescription(t *testing.T) {
+	data := map[Currency]string{
+		CurrencyUSD: "United States dollar",
+		CurrencyEUR: "Euro",
+		CurrencyJPY: "Japanese yen",
+		0:           "Unknown",
+	}
+
+	for currency, desc := range data {
+		t.Run(desc, func(t *testing.T) {
+			assertEqual(t, currency.Description(), desc)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Currency message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,16 @@
+// Package direction is used for testing purpose only
+package direction
+
+//go:generate cmtstringer -type Direction -linecomment direction.go
+
+// Direction type of compass direction constant documented by line comments
+type Direction int
+
+const (
+	North Direction = iota // Up
+	East                   // East Right
+	// South Doc comment of south
+	South // Down
+	// West Left
+	West
+)
@@ -0,0 +1,27 @@
+package direction
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDirectionMessage(t *testing.T) {
+	data := map[Direction]string{
+		North: "Up",
+		East:  "Right",
+		South: "Down",
+		West:  "Left",
+	}
+
+	for direction, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", direction), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Direction message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,18 @@
+// Package exitcode is used for testing purpose only
+package exitcode
+
+//go:generate cmtstringer -type ExitCode -gostring -values -index -sort name
+
+// ExitCode type of signed constant with a sentinel
+type ExitCode int8
+
+const (
+	// Unset Exit code isn't set yet
+	Unset ExitCode = -1
+	// Success Successful termination
+	Success ExitCode = 0x0
+	// Failure General failure
+	Failure ExitCode = 0x01
+	// Usage Command line usage error
+	Usage ExitCode = 02
+)
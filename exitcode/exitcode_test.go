@@ -0,0 +1,71 @@
+package exitcode
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestExitCodeMessage(t *testing.T) {
+	data := map[ExitCode]string{
+		Unset:   "Exit code isn't set yet",
+		Success: "Successful termination",
+		Failure: "General failure",
+		Usage:   "Command line usage error",
+	}
+
+	for code, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", code), msg)
+		})
+	}
+}
+
+func TestUnknownExitCodeMessage(t *testing.T) {
+	for _, code := range []ExitCode{-128, -2, 3, 127} {
+		assertEqual(t, fmt.Sprintf("%v", code), "Unknown")
+	}
+}
+
+func TestExitCodeGoString(t *testing.T) {
+	data := map[ExitCode]string{
+		Unset: "exitcode.Unset",
+		Usage: "exitcode.Usage",
+		-2:    "exitcode.ExitCode(-2)",
+	}
+
+	for code, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%#v", code), msg)
+		})
+	}
+}
+
+func TestExitCodeValues(t *testing.T) {
+	// Constants are sorted by name.
+	assertEqual(t, fmt.Sprintf("%#v", ExitCodeValues()), "[]exitcode.ExitCode{exitcode.Failure, exitcode.Success, exitcode.Unset, exitcode.Usage}")
+}
+
+func TestExitCodeIndex(t *testing.T) {
+	// Constants are indexed in the order of declaration, whichever the sort order.
+	data := map[ExitCode]int{
+		Unset:   0,
+		Success: 1,
+		Failure: 2,
+		Usage:   3,
+		-2:      -1,
+	}
+
+	for code, index := range data {
+		t.Run(fmt.Sprint(index), func(t *testing.T) {
+			if code.Index() != index {
+				t.Fatalf("ExitCode index is incorrect\nExpected: %d\nObtained: %d", index, code.Index())
+			}
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("ExitCode message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
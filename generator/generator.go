@@ -0,0 +1,1800 @@
+// Package generator generates String methods returning comments of constants,
+// along with the other methods of command cmtstringer, so that it can be used
+// by other generators and tests without running the command.
+//
+// 	src, err := generator.Generate(generator.Config{
+// 		Dir:   "./http",
+// 		Types: []string{"StatusCode"},
+// 		Parse: true,
+// 	})
+//
+package generator // import "github.com/lazada/cmtstringer/generator"
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/constant"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"go/version"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/imports"
+)
+
+// StdoutName is the output file name meaning standard output.
+const StdoutName = "-"
+
+// generatedMarker matches the comment line telling generated files from hand-written ones,
+// following the Go convention, and legacyMarker is the one of files generated by earlier versions.
+var (
+	generatedMarker = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+	legacyMarker    = "// This file is generated by command cmtstringer."
+)
+
+// Config represents the package to generate methods for, and the options of
+// command cmtstringer of the same names. The zero value of an option is its default.
+type Config struct {
+	// Dir is the directory of the package. If File is set, only the constants
+	// of the file are taken, and only its package is generated.
+	Dir  string
+	File string
+
+	// Types are the names of the types to generate methods for.
+	// With All, they are all the integer and string types of the package
+	// having constants with comments, each of them generated into its own file.
+	Types []string
+	All   bool
+
+	// Tags are the build tags to apply when loading the package.
+	// With PerTag, a file constrained to each of the tags is generated with
+	// the constants visible with the tag, along with a file constrained to
+	// none of them, so the tags must be mutually exclusive, e.g. GOOS values.
+	Tags         []string
+	PerTag       bool
+	IncludeTests bool
+
+	// SkipUndeclared ignores the types not declared in the directory,
+	// so that no files are generated if none of them is declared.
+	SkipUndeclared bool
+
+	// Output is the name of the single generated file, or StdoutName.
+	// By default files are named after the types with Suffix, "_string_gen.go"
+	// unless set, in OutputDir, or Dir unless set.
+	Output      string
+	OutputDir   string
+	Suffix      string
+	PackageName string
+
+	// Match is the regular expression which names of included constants must match.
+	Match *regexp.Regexp
+
+	// IncludeUnexported includes unexported constants as well.
+	IncludeUnexported bool
+
+	// Options of taking messages from comments
+	TrimPrefix     string
+	Multiline      bool
+	NoNamePrefix   bool
+	NameFallback   bool
+	AutoTrimPrefix bool
+	Strict         bool
+	LineComment    bool
+
+	// Messages are the messages by constant names used instead of their comments,
+	// which are used for the other constants. See LoadMessages.
+	Messages map[string]string
+
+	// Locales are the messages by constant names of each language, which are
+	// returned by the generated StringIn method, or its messages for the others.
+	Locales map[string]map[string]string
+
+	// StripMarkup removes HTML tags from messages and unescapes &amp;, &lt; and &gt;.
+	StripMarkup bool
+
+	// MsgPrefix is prepended to every message along with a space, e.g. "HTTP:",
+	// including the ones of Locales. With Description, it's prepended to the comments, not the names.
+	MsgPrefix string
+
+	// Dedupe makes the comments of constants having the same value as a constant
+	// before them parsed back as the latter, which String returns for both of them.
+	// Otherwise their comments are ignored.
+	Dedupe bool
+
+	// Options of generated code
+	Parse           bool
+	JSON            bool
+	Text            bool
+	YAML            bool
+	XML             bool
+	SQL             bool
+	FlagValue       bool
+	CaseInsensitive bool
+	IsValid         bool
+	Validate        bool
+	Checked         bool
+	Description     bool
+	GoString        bool
+	Values          bool
+	Append          bool
+	Guard           bool
+	Int             bool
+	Index           bool
+	Bitmask         bool
+	Registry        bool
+	Map             bool
+	Ptr             bool
+	Assert          bool
+
+	// Sort is the order of generated cases and values: source, value or name.
+	Sort string
+
+	// Receiver is the receiver name of generated methods, computed from the type unless set.
+	Receiver string
+
+	// Method is the name of the method returning comments, String unless set.
+	Method string
+
+	// Format is the formatting of generated code: gofmt, or goimports.
+	Format string
+
+	// BitmaskZero is the message of the zero value of Bitmask types without
+	// a constant of it, "0" if nil.
+	BitmaskZero *string
+
+	// Compat is the oldest Go version the generated code must compile with, e.g. go1.16.
+	// Before go1.17, build constraints are written as "// +build" lines as well.
+	// The other generated code compiles with go1.2 and later, which is the oldest
+	// version allowed, as encoding.TextMarshaler was added in go1.2.
+	Compat string
+
+	// Test generates a test of the generated code in <output>_test.go as well.
+	Test bool
+
+	// Header is the comment put before the package clause, see LoadHeader.
+	Header string
+
+	// NoDefault omits the default case for unknown values: panic or numeric.
+	NoDefault string
+
+	// Default is the message of unknown values, "Unknown" if nil.
+	// It may contain a fmt verb for the value, or be panic to panic instead.
+	Default *string
+
+	// Template replaces the built-in template, see LoadTemplate.
+	Template *template.Template
+
+	// Logf logs warnings, and Verbosef logs processed types and constants.
+	// Messages aren't logged unless set.
+	Logf     func(format string, args ...interface{})
+	Verbosef func(format string, args ...interface{})
+
+	// build is the build constraint of the files generated for a tag of PerTag,
+	// and tag is the tag appended to their default names.
+	build, tag string
+}
+
+// File represents a generated file.
+type File struct {
+	Name   string
+	Source []byte
+}
+
+// constValue represents information of an constant
+type constValue struct {
+	Name  string
+	Msg   string
+	Value string // canonical value, e.g. 1 for an iota constant or 31 for 0x1F
+	Desc  string // comment returned by Description, when Msg is the name
+	Index int    // position in the order of declaration, returned by Index
+
+	// Aliases are the constants of the same value with other comments,
+	// which are parsed back as this one with Dedupe.
+	Aliases []constValue
+
+	val       constant.Value
+	pos       token.Position
+	isDefault bool
+}
+
+// bitmaskValue represents the single-bit constants of a bitmask type,
+// which comments are joined by "|" for the values not declared.
+type bitmaskValue struct {
+	Bits []constValue
+
+	// Zero is the message of the zero value, unless HasZero is set
+	// as a constant of the zero value is declared.
+	Zero    string
+	HasZero bool
+}
+
+// bitmaskValues returns the single-bit constants of the integer constants, in their order.
+func bitmaskValues(values []constValue, zero string) *bitmaskValue {
+	bitmask := &bitmaskValue{Zero: zero}
+	one := constant.MakeInt64(1)
+	for _, v := range values {
+		if constant.Sign(v.val) == 0 {
+			bitmask.HasZero = true
+		}
+		// Powers of two have a single bit, as x&(x-1) is zero.
+		prev := constant.BinaryOp(v.val, token.SUB, one)
+		if constant.Sign(v.val) > 0 && constant.Sign(constant.BinaryOp(v.val, token.AND, prev)) == 0 {
+			bitmask.Bits = append(bitmask.Bits, v)
+		}
+	}
+	return bitmask
+}
+
+// packedValue represents comments of constants having contiguous integer values,
+// packed into a single string and indexed by value like stringer does.
+type packedValue struct {
+	Names     string
+	Index     []int
+	IndexType string
+	Min       string
+}
+
+// genOptions represents options of generated code
+type genOptions struct {
+	Parse    bool
+	JSON     bool
+	SQL      bool
+	Text     bool
+	YAML     bool
+	XML      bool
+	IsValid  bool
+	Validate bool
+	Checked  bool
+	Values   bool
+	Desc     bool
+	GoString bool
+	Append   bool
+	Guard    bool
+	Int      bool
+	Index    bool
+	Ptr      bool
+	Registry bool
+	Map      bool
+	Assert   bool
+	StringIn bool
+
+	// FlagValue generates Set, which makes the type a flag.Value
+	// along with the method returning comments if it is named String.
+	FlagValue bool
+
+	// Method is the name of the method returning comments.
+	Method string
+
+	// CaseInsensitive makes Parse match comments regardless of case.
+	CaseInsensitive bool
+
+	// Header is the comment put before the package clause.
+	Header string
+
+	// Default is the message of unknown values.
+	// When DefaultFormat is set, it is a format string of the value.
+	Default       string
+	DefaultFormat bool
+
+	// NoDefault omits the default case of the String switch,
+	// and DefaultPanic makes String panic for unknown values.
+	NoDefault    bool
+	DefaultPanic bool
+}
+
+// typeValue represents information of a const type and its constants
+type typeValue struct {
+	genOptions
+	PackageName string
+	TypeName    string
+	Receiver    string
+	Consts      []constValue
+	Packed      *packedValue
+	Bitmask     *bitmaskValue
+
+	// Langs are the messages of the constants in the languages of Locales, by language.
+	Langs []langValue
+
+	// Underlying is the name of the underlying basic type, e.g. uint8 for byte,
+	// or empty if the underlying type isn't basic.
+	Underlying string
+
+	// Verb is the fmt verb of values in error messages: %d for integers, %q for strings, or %v.
+	Verb string
+}
+
+// imports returns sorted paths of the packages used by the generated code.
+func (o genOptions) imports() []string {
+	required := map[string]bool{
+		"fmt":                 o.Parse || o.DefaultFormat || o.DefaultPanic || o.GoString || o.Text || o.YAML || o.XML || o.Validate || o.Assert && o.Method == "String",
+		"encoding":            o.Text && o.Assert,
+		"encoding/json":       o.JSON,
+		"encoding/xml":        o.XML,
+		"flag":                o.FlagValue,
+		"database/sql":        o.SQL && o.Assert,
+		"database/sql/driver": o.SQL,
+		"strings":             o.Parse && o.CaseInsensitive,
+	}
+
+	var paths []string
+	for path, ok := range required {
+		if ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// methods reports by name whether the methods besides the one returning comments are generated.
+func (o genOptions) methods() map[string]bool {
+	return map[string]bool{
+		"MarshalJSON":   o.JSON,
+		"UnmarshalJSON": o.JSON,
+		"MarshalText":   o.Text,
+		"UnmarshalText": o.Text,
+		"MarshalYAML":   o.YAML,
+		"UnmarshalYAML": o.YAML,
+		"MarshalXML":    o.XML,
+		"UnmarshalXML":  o.XML,
+		"Scan":          o.SQL,
+		"Value":         o.SQL,
+		"Set":           o.FlagValue,
+		"IsValid":       o.IsValid,
+		"Validate":      o.Validate,
+		"StringOK":      o.Checked,
+		"Description":   o.Desc,
+		"GoString":      o.GoString,
+		"Append":        o.Append,
+		"Int":           o.Int,
+		"Index":         o.Index,
+		"StringIn":      o.StringIn,
+	}
+}
+
+// typesImports returns sorted paths of the packages used by the code generated
+// for the types, whose options may differ from the ones of the file, e.g. Default.
+func typesImports(types []typeValue) []string {
+	required := make(map[string]bool)
+	var paths []string
+	for _, tv := range types {
+		for _, path := range tv.imports() {
+			if !required[path] {
+				required[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// fileValue represents information of a generated file
+type fileValue struct {
+	genOptions
+	PackageName string
+	Imports     []string
+	Types       []typeValue
+
+	// Build is the build constraint of the file, if any,
+	// and PlusBuild its "// +build" lines for Go versions before go1.17.
+	Build     string
+	PlusBuild []string
+}
+
+// langValue represents the constants having messages in a language of Locales.
+type langValue struct {
+	Lang   string
+	Consts []constValue
+}
+
+// langValues returns the constants having messages in each language of the locales,
+// with the prefix prepended, sorted by language so the generated code is always the same.
+func langValues(locales map[string]map[string]string, values []constValue, prefix string) []langValue {
+	langs := make([]string, 0, len(locales))
+	for lang := range locales {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	var langValues []langValue
+	for _, lang := range langs {
+		lv := langValue{Lang: lang}
+		for _, v := range values {
+			if msg, ok := locales[lang][v.Name]; ok {
+				lv.Consts = append(lv.Consts, constValue{Name: v.Name, Msg: prefixed(prefix, msg)})
+			}
+		}
+		langValues = append(langValues, lv)
+	}
+	return langValues
+}
+
+// logf logs the warning if Logf is set.
+func (c *Config) logf(format string, args ...interface{}) {
+	if c.Logf != nil {
+		c.Logf(format, args...)
+	}
+}
+
+// verbosef logs the message if Verbosef is set.
+func (c *Config) verbosef(format string, args ...interface{}) {
+	if c.Verbosef != nil {
+		c.Verbosef(format, args...)
+	}
+}
+
+// options returns the options of generated code, or an error if any of them is unknown.
+func (c *Config) options() (genOptions, error) {
+	if len(c.Types) == 0 && !c.All {
+		return genOptions{}, fmt.Errorf("no types given")
+	}
+	if len(c.Types) > 0 && c.All {
+		return genOptions{}, fmt.Errorf("types %s can't be given along with all types", strings.Join(c.Types, ","))
+	}
+	if c.All && c.Output != "" {
+		return genOptions{}, fmt.Errorf("output file %s can't be used for a file per type", c.Output)
+	}
+	if c.Sort != "" && c.Sort != "source" && c.Sort != "value" && c.Sort != "name" {
+		return genOptions{}, fmt.Errorf("unknown sort order %q, must be source, value or name", c.Sort)
+	}
+	if c.Format != "" && c.Format != "gofmt" && c.Format != "goimports" {
+		return genOptions{}, fmt.Errorf("unknown format %q, must be gofmt or goimports", c.Format)
+	}
+	if c.Test && c.Output == StdoutName {
+		return genOptions{}, fmt.Errorf("generated test can't be written to standard output")
+	}
+	if c.PerTag {
+		if c.Output != "" {
+			return genOptions{}, fmt.Errorf("output file %s can't be used for a file per tag", c.Output)
+		}
+		for _, tag := range c.Tags {
+			if !buildTag.MatchString(tag) {
+				return genOptions{}, fmt.Errorf("invalid build tag %q of a file per tag", tag)
+			}
+		}
+	}
+	for lang := range c.Locales {
+		if lang == "" {
+			return genOptions{}, fmt.Errorf("messages of empty language")
+		}
+	}
+	if c.Compat != "" {
+		if !version.IsValid(c.Compat) {
+			return genOptions{}, fmt.Errorf("invalid Go version %q, must be e.g. go1.16", c.Compat)
+		}
+		if version.Compare(c.Compat, minCompat) < 0 {
+			return genOptions{}, fmt.Errorf("generated code doesn't compile with Go version %s, it requires %s or later", c.Compat, minCompat)
+		}
+	}
+	if c.PackageName != "" && !token.IsIdentifier(c.PackageName) {
+		return genOptions{}, fmt.Errorf("invalid package name %q", c.PackageName)
+	}
+	method := c.Method
+	if method == "" {
+		method = "String"
+	}
+	if !token.IsIdentifier(method) {
+		return genOptions{}, fmt.Errorf("invalid method name %q", method)
+	}
+	// The receiver is used in method bodies, so it can't be blank.
+	if c.Receiver != "" && (!token.IsIdentifier(c.Receiver) || c.Receiver == "_") {
+		return genOptions{}, fmt.Errorf("invalid receiver name %q", c.Receiver)
+	}
+	if c.FlagValue && method != "String" {
+		return genOptions{}, fmt.Errorf("flag.Value requires String, it can't be generated along with method %s", method)
+	}
+
+	defaultMsg := "Unknown"
+	if c.Default != nil {
+		defaultMsg = *c.Default
+	}
+	opts := genOptions{
+		Parse:    c.Parse || c.JSON || c.SQL || c.Text || c.YAML || c.XML || c.FlagValue,
+		JSON:     c.JSON,
+		SQL:      c.SQL,
+		Text:     c.Text,
+		YAML:     c.YAML,
+		XML:      c.XML,
+		IsValid:  c.IsValid,
+		Validate: c.Validate,
+		Checked:  c.Checked,
+		Desc:     c.Description,
+		Values:   c.Values || c.Test,
+		GoString: c.GoString,
+		Append:   c.Append,
+		Guard:    c.Guard,
+		Int:      c.Int,
+		Index:    c.Index,
+		Ptr:      c.Ptr,
+		Registry: c.Registry,
+		Map:      c.Map,
+		Assert:   c.Assert,
+		StringIn: len(c.Locales) > 0,
+
+		FlagValue: c.FlagValue,
+
+		Method: method,
+
+		CaseInsensitive: c.CaseInsensitive,
+
+		Header: c.Header,
+
+		Default:       defaultMsg,
+		DefaultFormat: hasVerb(defaultMsg),
+		DefaultPanic:  defaultMsg == "panic",
+	}
+	switch c.NoDefault {
+	case "":
+	case "panic":
+		opts.NoDefault, opts.DefaultPanic = true, true
+	case "numeric":
+		opts.NoDefault, opts.Default, opts.DefaultFormat = true, "%v", true
+	default:
+		return genOptions{}, fmt.Errorf("unknown no-default %q, must be panic or numeric", c.NoDefault)
+	}
+	if opts.methods()[method] {
+		return genOptions{}, fmt.Errorf("method %s is generated by another option, it can't return comments", method)
+	}
+	return opts, nil
+}
+
+// hasVerb reports whether the message has a fmt verb, e.g. %d or %-5v, so it's
+// a format string of the value. A literal %, e.g. of "100% sure", isn't one,
+// and neither is %%.
+func hasVerb(msg string) bool {
+	for i := 0; i < len(msg); i++ {
+		if msg[i] != '%' {
+			continue
+		}
+		j := i + 1
+		for j < len(msg) && strings.IndexByte("+-#0123456789.", msg[j]) >= 0 {
+			j++
+		}
+		if j < len(msg) && strings.IndexByte("bcdeEfFgGoOqstTUvxX", msg[j]) >= 0 {
+			return true
+		}
+		if j < len(msg) && msg[j] == '%' && j == i+1 {
+			i = j
+		}
+	}
+	return false
+}
+
+// Generate returns the formatted source of the single file generated for the
+// package in cfg.Dir. Use GenerateFiles when several files are generated,
+// e.g. along with a test, or for the external test package as well.
+func Generate(cfg Config) ([]byte, error) {
+	files, err := GenerateFiles(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) != 1 {
+		return nil, fmt.Errorf("%d files generated instead of one", len(files))
+	}
+	return files[0].Source, nil
+}
+
+// GenerateFiles returns the formatted files generated for the packages in cfg.Dir.
+// Like the command, it doesn't generate a package without constants of the types,
+// and fails if a type isn't declared unless cfg.SkipUndeclared is set.
+func GenerateFiles(cfg Config) ([]File, error) {
+	c := &cfg
+	opts, err := c.options()
+	if err != nil {
+		return nil, err
+	}
+	if c.PerTag && len(c.Tags) > 0 {
+		return generatePerTag(cfg)
+	}
+	tmpl := fileTemplate
+	if c.Template != nil {
+		tmpl = c.Template
+	}
+
+	dir := c.Dir
+	switch {
+	case dir == "" && c.File != "":
+		dir = filepath.Dir(c.File)
+	case dir == "":
+		dir = "."
+	}
+	fileName := c.File
+	if fileName != "" {
+		if fileName, err = filepath.Abs(fileName); err != nil {
+			return nil, err
+		}
+	}
+
+	pkgs, err := c.loadPackages(dir)
+	if err != nil {
+		return nil, err
+	}
+	if fileName != "" {
+		pkgs, err = filePackage(pkgs, fileName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pkgNames := make([]string, 0, len(pkgs))
+	for pkgName := range pkgs {
+		pkgNames = append(pkgNames, pkgName)
+	}
+	sort.Strings(pkgNames)
+
+	type outputFile struct {
+		name string
+		data fileValue
+	}
+
+	var outputs []outputFile
+	declared := make(map[string]bool)
+	found := make(map[string]bool)
+
+	for _, pkgName := range pkgNames {
+		pkg := pkgs[pkgName]
+		typesPkg := pkg.Types
+		consts := c.packageConsts(pkg, fileName)
+
+		genPkgName := pkgName
+		if c.PackageName != "" {
+			genPkgName = c.PackageName
+		}
+
+		tmplData := fileValue{
+			genOptions:  opts,
+			PackageName: genPkgName,
+			Imports:     opts.imports(),
+			Build:       c.build,
+		}
+		if c.build != "" && c.Compat != "" && version.Compare(c.Compat, "go1.17") < 0 {
+			if tmplData.PlusBuild, err = plusBuildLines(c.build); err != nil {
+				return nil, err
+			}
+		}
+
+		typeNames := c.Types
+		if c.All {
+			typeNames = enumTypes(consts, typesPkg)
+			c.verbosef("%s: found %d types with constants in package %s", dir, len(typeNames), pkgName)
+		}
+
+		for _, typeName := range typeNames {
+			obj, ok := typesPkg.Scope().Lookup(typeName).(*types.TypeName)
+			if ok {
+				declared[typeName] = true
+			}
+
+			// Methods can't be defined on an alias, so they are generated for the aliased type,
+			// which must be a defined type of the same package.
+			methodType := typeName
+			if ok && obj.IsAlias() {
+				aliased := types.Unalias(obj.Type())
+				named, isNamed := aliased.(*types.Named)
+				if !isNamed || named.Obj().Pkg() != typesPkg {
+					return nil, fmt.Errorf("type %s is an alias of %s, methods can't be defined on it",
+						typeName, types.TypeString(aliased, types.RelativeTo(typesPkg)))
+				}
+				obj = named.Obj()
+				methodType = obj.Name()
+			}
+
+			// Constants declared with the alias are of the aliased type as well.
+			values, skipped, foreign := c.typeConsts(consts, typesPkg, methodType)
+			if foreign != nil {
+				// Methods can't be defined on a type of another package,
+				// so tell why the constants are missing rather than skip them silently.
+				foreignType := types.TypeString(foreign.named, types.RelativeTo(typesPkg))
+				if !ok && !c.SkipUndeclared {
+					return nil, fmt.Errorf("type %s isn't declared in package %s, but %s declares a constant of type %s: "+
+						"methods must be generated in the package declaring the type", typeName, typesPkg.Name(), foreign.pos, foreignType)
+				}
+				c.logf("%s: constants of type %s are skipped, methods can't be defined on a type of another package",
+					foreign.pos, foreignType)
+			}
+			if c.Match != nil {
+				c.verbosef("%s: %d constants of type %s match %s, %d skipped", dir, len(values), methodType, c.Match, skipped)
+			}
+
+			if len(values) == 0 || c.All && !hasMessages(values) {
+				continue
+			}
+			sortByPosition(values)
+			defaultConst, err := defaultValue(methodType, values)
+			if err != nil {
+				return nil, err
+			}
+			if defaultConst != nil && opts.NoDefault {
+				return nil, fmt.Errorf("%s: constant %s is marked %s, but the default case is omitted",
+					defaultConst.pos, defaultConst.Name, defaultDirective)
+			}
+			values = c.uniqueValues(dir, values)
+			for i := range values {
+				values[i].Index = i
+			}
+			c.sortValues(values)
+			found[typeName] = true
+
+			if c.AutoTrimPrefix {
+				prefix := namePrefix(values)
+				c.verbosef("%s: constants of type %s have the common prefix %q", dir, methodType, prefix)
+				for i, v := range values {
+					if v.Msg == "" {
+						values[i].Msg = strings.TrimPrefix(v.Name, prefix)
+					}
+					for j, a := range v.Aliases {
+						if a.Msg == "" {
+							v.Aliases[j].Msg = strings.TrimPrefix(a.Name, prefix)
+						}
+					}
+				}
+			}
+
+			// With Description, String returns the names and Description the comments.
+			if opts.Desc {
+				for i, v := range values {
+					values[i].Desc = v.Msg
+					if values[i].Msg = strings.TrimPrefix(v.Name, c.TrimPrefix); values[i].Msg == "" {
+						values[i].Msg = v.Name
+					}
+					for j, a := range v.Aliases {
+						if v.Aliases[j].Msg = strings.TrimPrefix(a.Name, c.TrimPrefix); v.Aliases[j].Msg == "" {
+							v.Aliases[j].Msg = a.Name
+						}
+					}
+				}
+			}
+			for i := range values {
+				values[i].Aliases = distinctAliases(values[i])
+			}
+			// With Description, the comments are prefixed rather than the names.
+			if opts.Desc {
+				for i, v := range values {
+					values[i].Desc = prefixed(c.MsgPrefix, v.Desc)
+				}
+			} else {
+				prefixMessages(values, c.MsgPrefix)
+			}
+
+			// Comments are told apart when parsed back, or in strict mode.
+			if tmplData.Parse || tmplData.Registry || c.Strict {
+				if err := checkDuplicateMessages(methodType, withAliases(values)); err != nil {
+					return nil, err
+				}
+			}
+			if tmplData.Parse && tmplData.CaseInsensitive {
+				var lowered []constValue
+				for _, v := range withAliases(values) {
+					lowered = append(lowered, constValue{Name: v.Name, Msg: strings.ToLower(v.Msg)})
+				}
+				if err := checkDuplicateMessages(methodType, lowered); err != nil {
+					return nil, fmt.Errorf("case-insensitive parsing: %v", err)
+				}
+			}
+
+			tv := typeValue{
+				genOptions:  opts,
+				PackageName: genPkgName,
+				TypeName:    methodType,
+				Receiver:    c.receiverName(methodType, typesPkg.Scope(), opts),
+				Consts:      values,
+			}
+			if opts.StringIn {
+				tv.Langs = langValues(c.Locales, values, c.MsgPrefix)
+			}
+			if defaultConst != nil {
+				// Constants of the same value share the message, even if the marked one is ignored.
+				for _, v := range values {
+					if v.Value == defaultConst.Value {
+						tv.Default, tv.DefaultFormat, tv.DefaultPanic = v.Msg, false, false
+					}
+				}
+				c.verbosef("%s: unknown values of type %s are %q of %s", dir, methodType, tv.Default, defaultConst.Name)
+			}
+			c.verbosef("%s: type %s has %d constants", dir, methodType, len(values))
+			// Values guarded by array indexes and returned by Int must be integers.
+			tv.Guard, tv.Int = false, false
+			tv.Verb = "%v"
+			if obj != nil {
+				if basic, ok := obj.Type().Underlying().(*types.Basic); ok {
+					tv.Underlying = basic.Name()
+					switch {
+					case basic.Info()&types.IsInteger != 0:
+						tv.Verb = "%d"
+					case basic.Info()&types.IsString != 0:
+						tv.Verb = "%q"
+					}
+					// Values can index the packed comments only if they are integers.
+					if basic.Info()&types.IsInteger != 0 {
+						tv.Packed = packValues(values)
+						tv.Guard, tv.Int = opts.Guard, opts.Int
+					}
+					// Combined bits are told by their single bits instead of packed comments.
+					if c.Bitmask && basic.Info()&types.IsInteger != 0 {
+						zero := "0"
+						if c.BitmaskZero != nil {
+							zero = *c.BitmaskZero
+						}
+						tv.Packed, tv.Bitmask = nil, bitmaskValues(values, zero)
+						c.verbosef("%s: type %s has %d single-bit constants", dir, methodType, len(tv.Bitmask.Bits))
+					}
+				}
+			}
+			if c.Bitmask && tv.Bitmask == nil && !c.All {
+				return nil, fmt.Errorf("bitmask type %s isn't of an integer type", methodType)
+			}
+			if opts.Int && !tv.Int && !c.All {
+				return nil, fmt.Errorf("type %s of Int method isn't of an integer type", methodType)
+			}
+			if opts.Guard && !tv.Guard && !c.All {
+				return nil, fmt.Errorf("guarded type %s isn't of an integer type", methodType)
+			}
+
+			if c.All {
+				typeData := tmplData
+				typeData.Types = []typeValue{tv}
+				typeData.Imports = typesImports(typeData.Types)
+				outputName := c.defaultOutputName(dir, pkgName, []string{methodType})
+				outputs = append(outputs, outputFile{name: outputName, data: typeData})
+				continue
+			}
+			tmplData.Types = append(tmplData.Types, tv)
+		}
+
+		if len(tmplData.Types) == 0 {
+			continue
+		}
+		tmplData.Imports = typesImports(tmplData.Types)
+
+		outputName := c.Output
+		if outputName == "" {
+			outputName = c.defaultOutputName(dir, pkgName, c.Types)
+		}
+
+		outputs = append(outputs, outputFile{name: outputName, data: tmplData})
+	}
+
+	if c.Output != "" && c.Output != StdoutName && len(outputs) > 1 {
+		return nil, fmt.Errorf("output file %s can't be used for %d packages", c.Output, len(outputs))
+	}
+
+	// Walking subdirectories, only the ones declaring the types are generated.
+	if c.SkipUndeclared && len(declared) == 0 {
+		return nil, nil
+	}
+	if c.All && len(outputs) == 0 {
+		c.logf("%s: no types with commented constants found", dir)
+	}
+
+	for _, typeName := range c.Types {
+		switch {
+		case !declared[typeName] && c.SkipUndeclared:
+			// Other subdirectories may declare the type.
+		case !declared[typeName]:
+			return nil, fmt.Errorf("no declared type %q found", typeName)
+		case !found[typeName] && c.IncludeUnexported:
+			c.logf("no constants of type %q found", typeName)
+		case !found[typeName]:
+			c.logf("no exported constants of type %q found", typeName)
+		}
+	}
+
+	var files []File
+	for _, out := range outputs {
+		src, err := c.render(out.name, tmpl, out.data)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: out.name, Source: src})
+
+		// Files of external test packages are tests themselves.
+		if c.Test && !strings.HasSuffix(out.name, "_test.go") {
+			testName := strings.TrimSuffix(out.name, ".go") + "_test.go"
+			src, err := c.render(testName, testFileTemplate, out.data)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, File{Name: testName, Source: src})
+		}
+	}
+
+	return files, nil
+}
+
+// minCompat is the oldest Go version which the generated code compiles with.
+const minCompat = "go1.2"
+
+// plusBuildLines returns the "// +build" lines of the build constraint expression.
+func plusBuildLines(build string) ([]string, error) {
+	expr, err := constraint.Parse("//go:build " + build)
+	if err != nil {
+		return nil, err
+	}
+	return constraint.PlusBuildLines(expr)
+}
+
+// buildTag matches the build tags which can be the constraint of a file.
+var buildTag = regexp.MustCompile(`^[\w.]+$`)
+
+// generatePerTag returns the files generated with each of the tags on its own,
+// and the file generated with none of them, unless the types are declared only
+// in files constrained to the tags.
+func generatePerTag(cfg Config) ([]File, error) {
+	var files []File
+	for _, tag := range cfg.Tags {
+		tagCfg := cfg
+		tagCfg.Tags, tagCfg.PerTag, tagCfg.build, tagCfg.tag = []string{tag}, false, tag, tag
+		tagFiles, err := GenerateFiles(tagCfg)
+		if err != nil {
+			return nil, fmt.Errorf("tag %s: %v", tag, err)
+		}
+		files = append(files, tagFiles...)
+	}
+
+	noTags := make([]string, len(cfg.Tags))
+	for i, tag := range cfg.Tags {
+		noTags[i] = "!" + tag
+	}
+	noTagCfg := cfg
+	noTagCfg.Tags, noTagCfg.PerTag, noTagCfg.build = nil, false, strings.Join(noTags, " && ")
+	noTagCfg.SkipUndeclared = true
+	noTagFiles, err := GenerateFiles(noTagCfg)
+	if err != nil {
+		return nil, err
+	}
+	return append(files, noTagFiles...), nil
+}
+
+// defaultOutputName returns the default name of the file generated for the package.
+// All types given in one invocation share a file named after the package,
+// unless a single type is given.
+// Files generated for a tag of PerTag end with the tag before the extension,
+// and files of external test packages end with _test.go to be compiled along with them.
+func (c *Config) defaultOutputName(dir, pkgName string, typeNames []string) string {
+	isTest := strings.HasSuffix(pkgName, "_test")
+
+	suffix := c.Suffix
+	if suffix == "" {
+		suffix = "_string_gen.go"
+	}
+	baseName := strings.TrimSuffix(pkgName, "_test") + suffix
+	if len(typeNames) == 1 {
+		baseName = typeNames[0] + suffix
+	}
+	if c.tag != "" {
+		ext := filepath.Ext(baseName)
+		baseName = strings.TrimSuffix(baseName, ext) + "_" + c.tag + ext
+	}
+	if isTest {
+		ext := filepath.Ext(baseName)
+		baseName = strings.TrimSuffix(baseName, ext) + "_test" + ext
+	}
+
+	outDir := dir
+	if c.OutputDir != "" {
+		outDir = c.OutputDir
+	}
+	return filepath.Join(outDir, strings.ToLower(baseName))
+}
+
+// packageConst represents a constant declared in the package, with its comments.
+type packageConst struct {
+	obj          *types.Const
+	doc, comment *ast.CommentGroup
+	pos, specPos token.Position
+
+	// isDefault is set by the default directive.
+	isDefault bool
+}
+
+// packageConsts returns the exported constants of the package, or all of them with
+// IncludeUnexported, in a single pass over the syntax and the type checking info,
+// which are shared by all the types. If fileName is set, other files are skipped.
+func (c *Config) packageConsts(pkg *packages.Package, fileName string) []packageConst {
+	var consts []packageConst
+	for _, f := range pkg.Syntax {
+		if fileName != "" && pkg.Fset.Position(f.Pos()).Filename != fileName {
+			continue
+		}
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			if gd.Tok != token.CONST {
+				continue
+			}
+
+			for _, s := range gd.Specs {
+				vs := s.(*ast.ValueSpec)
+
+				// The comment above an unparenthesized "const X T = 1" is the doc of the declaration.
+				doc := vs.Doc
+				if doc == nil && !gd.Lparen.IsValid() {
+					doc = gd.Doc
+				}
+				if hasDirective(doc, skipDirective) || hasDirective(vs.Comment, skipDirective) {
+					c.verbosef("%s: %s skipped by %s", pkg.Fset.Position(vs.Pos()), identNames(vs.Names), skipDirective)
+					continue
+				}
+
+				for _, name := range vs.Names {
+					if name == nil || name.Name == "_" || !name.IsExported() && !c.IncludeUnexported {
+						continue
+					}
+
+					// The type is taken from type checking rather than the spec, so both "X T = 1"
+					// and "X = T(1)" are found, as well as "X" repeating the previous spec,
+					// as in iota sequences, and constants declared with an alias of the type.
+					obj, ok := pkg.TypesInfo.Defs[name].(*types.Const)
+					if !ok {
+						continue
+					}
+					consts = append(consts, packageConst{
+						obj:       obj,
+						doc:       doc,
+						comment:   vs.Comment,
+						pos:       pkg.Fset.Position(name.Pos()),
+						specPos:   pkg.Fset.Position(vs.Pos()),
+						isDefault: hasDirective(doc, defaultDirective) || hasDirective(vs.Comment, defaultDirective),
+					})
+				}
+			}
+		}
+	}
+	return consts
+}
+
+// skipDirective is the comment directive of constants which aren't generated,
+// e.g. a deprecated alias, and defaultDirective the one of the constant which
+// message is returned for unknown values. They aren't part of the comment text
+// as other directives.
+const (
+	skipDirective    = "//cmtstringer:skip"
+	defaultDirective = "//cmtstringer:default"
+)
+
+// hasDirective reports whether the comment group has the directive on a line of its own,
+// optionally followed by a space and a reason.
+func hasDirective(cg *ast.CommentGroup, directive string) bool {
+	if cg == nil {
+		return false
+	}
+	for _, comment := range cg.List {
+		if comment.Text == directive || strings.HasPrefix(comment.Text, directive+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// identNames returns the names of the identifiers joined by commas.
+func identNames(idents []*ast.Ident) string {
+	names := make([]string, len(idents))
+	for i, ident := range idents {
+		names[i] = ident.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// foreignConst represents the first constant of a type named the same as the one
+// to generate, but declared in another package.
+type foreignConst struct {
+	named *types.Named
+	pos   token.Position
+}
+
+// typeConsts returns the constants of the type declared in the package, along with
+// the number of them skipped for not matching Match, and the first constant of
+// a type of the same name declared in another package.
+func (c *Config) typeConsts(consts []packageConst, typesPkg *types.Package, typeName string) ([]constValue, int, *foreignConst) {
+	skipped := 0
+	var foreign *foreignConst
+	values := []constValue{}
+	for _, pc := range consts {
+		named, ok := types.Unalias(pc.obj.Type()).(*types.Named)
+		if !ok || named.Obj().Name() != typeName {
+			continue
+		}
+		if named.Obj().Pkg() != typesPkg {
+			if foreign == nil {
+				foreign = &foreignConst{named: named, pos: pc.specPos}
+			}
+			continue
+		}
+
+		constName := pc.obj.Name()
+		if c.Match != nil && !c.Match.MatchString(constName) {
+			skipped++
+			continue
+		}
+
+		values = append(values, constValue{
+			Name:  constName,
+			Msg:   c.constMessage(constName, pc.doc, pc.comment),
+			Value: pc.obj.Val().ExactString(),
+			val:   pc.obj.Val(),
+			pos:   pc.pos,
+
+			isDefault: pc.isDefault,
+		})
+	}
+
+	return values, skipped, foreign
+}
+
+// enumTypes returns the names of the integer and string types declared in the package
+// which constants are of, in the order of their first constants.
+func enumTypes(consts []packageConst, typesPkg *types.Package) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, pc := range consts {
+		named, ok := types.Unalias(pc.obj.Type()).(*types.Named)
+		if !ok || named.Obj().Pkg() != typesPkg || seen[named.Obj().Name()] {
+			continue
+		}
+		basic, ok := named.Underlying().(*types.Basic)
+		if !ok || basic.Info()&(types.IsInteger|types.IsString) == 0 {
+			continue
+		}
+		seen[named.Obj().Name()] = true
+		names = append(names, named.Obj().Name())
+	}
+	return names
+}
+
+// hasMessages reports whether any of the constants has a message.
+func hasMessages(values []constValue) bool {
+	for _, v := range values {
+		if v.Msg != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultValue returns the constant marked by the default directive, if any,
+// or an error if several constants of the type are marked.
+func defaultValue(typeName string, values []constValue) (*constValue, error) {
+	var marked *constValue
+	for i, v := range values {
+		if !v.isDefault {
+			continue
+		}
+		if marked != nil {
+			return nil, fmt.Errorf("constants %s and %s of type %s are both marked %s", marked.Name, v.Name, typeName, defaultDirective)
+		}
+		marked = &values[i]
+	}
+	if marked == nil {
+		return nil, nil
+	}
+	dflt := *marked
+	return &dflt, nil
+}
+
+// sortByPosition sorts the constants in the order of declaration, file by file
+// in the order of file names, so the generated code is always the same.
+func sortByPosition(values []constValue) {
+	sort.Slice(values, func(i, j int) bool {
+		pi, pj := values[i].pos, values[j].pos
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Offset < pj.Offset
+	})
+}
+
+// sortValues sorts the constants by value or by name as set by Sort.
+// Sorted by source, they keep the order of declaration.
+func (c *Config) sortValues(values []constValue) {
+	switch c.Sort {
+	case "value":
+		sort.SliceStable(values, func(i, j int) bool {
+			return lessValue(values[i].val, values[j].val)
+		})
+	case "name":
+		sort.SliceStable(values, func(i, j int) bool {
+			return values[i].Name < values[j].Name
+		})
+	}
+}
+
+// lessValue reports whether the value x is less than y. Bool values are ordered
+// false first, and complex values aren't ordered at all.
+func lessValue(x, y constant.Value) bool {
+	switch {
+	case x.Kind() == constant.Bool && y.Kind() == constant.Bool:
+		return !constant.BoolVal(x) && constant.BoolVal(y)
+	case x.Kind() == constant.Complex || y.Kind() == constant.Complex:
+		return false
+	}
+	return constant.Compare(x, token.LSS, y)
+}
+
+// uniqueValues returns the constants without the ones having the same value as
+// a constant before them, as duplicate cases or keys of the generated code don't compile.
+// With Dedupe, the latter are kept as aliases of the former.
+func (c *Config) uniqueValues(dir string, values []constValue) []constValue {
+	indexes := make(map[string]int, len(values))
+	var unique []constValue
+	for _, v := range values {
+		i, ok := indexes[v.Value]
+		switch {
+		case !ok:
+			indexes[v.Value] = len(unique)
+			unique = append(unique, v)
+		case c.Dedupe:
+			c.verbosef("%s: constant %s is an alias of %s", dir, v.Name, unique[i].Name)
+			unique[i].Aliases = append(unique[i].Aliases, v)
+		default:
+			c.verbosef("%s: constant %s has the same value as %s, its comment is ignored", dir, v.Name, unique[i].Name)
+		}
+	}
+	return unique
+}
+
+// distinctAliases returns the aliases of the constant with comments other than
+// its own and each other's, as the others can't be told apart when parsed.
+func distinctAliases(v constValue) []constValue {
+	var aliases []constValue
+	seen := map[string]bool{v.Msg: true, "": true}
+	for _, a := range v.Aliases {
+		if !seen[a.Msg] {
+			seen[a.Msg] = true
+			aliases = append(aliases, a)
+		}
+	}
+	return aliases
+}
+
+// prefixMessages prepends the prefix and a space to the messages
+// of the constants and their aliases.
+func prefixMessages(values []constValue, prefix string) {
+	for i, v := range values {
+		values[i].Msg = prefixed(prefix, v.Msg)
+		prefixMessages(v.Aliases, prefix)
+	}
+}
+
+// prefixed returns the message with the prefix and a space prepended,
+// or the message itself if either of them is empty.
+func prefixed(prefix, msg string) string {
+	if prefix == "" || msg == "" {
+		return msg
+	}
+	return prefix + " " + msg
+}
+
+// withAliases returns the constants along with their aliases.
+func withAliases(values []constValue) []constValue {
+	all := make([]constValue, 0, len(values))
+	for _, v := range values {
+		all = append(all, v)
+		all = append(all, v.Aliases...)
+	}
+	return all
+}
+
+// methodLocals are parameter and variable names used by the built-in
+// templates inside methods, which the receiver must not be named after.
+var methodLocals = map[string]bool{
+	"buf": true, "data": true, "err": true, "idx": true, "lang": true, "ok": true,
+	"raw": true, "rest": true, "src": true, "start": true, "str": true, "text": true,
+	"unmarshal": true, "val": true, "zero": true,
+}
+
+// receiverName returns the receiver name of the methods of the type. It is the
+// lowercased first letter of the type, or else its lowercased initials, which
+// are numbered until they don't shadow a package-level identifier of scope or
+// a parameter of the methods enabled by opts.
+func (c *Config) receiverName(typeName string, scope *types.Scope, opts genOptions) string {
+	if c.Receiver != "" {
+		return c.Receiver
+	}
+	var initials []rune
+	prev := '_'
+	for _, r := range typeName {
+		if unicode.IsLetter(r) && (prev == '_' || unicode.IsUpper(r) && !unicode.IsUpper(prev)) {
+			initials = append(initials, unicode.ToLower(r))
+		}
+		prev = r
+	}
+	if len(initials) == 0 {
+		initials = []rune{'x'}
+	}
+	usable := func(name string) bool {
+		switch {
+		case token.IsKeyword(name), methodLocals[name], scope.Lookup(name) != nil:
+			return false
+		case name == "b":
+			return !opts.Append
+		case name == "d", name == "e":
+			return !opts.XML
+		}
+		return true
+	}
+	first, all := string(initials[0]), string(initials)
+	if usable(first) {
+		return first
+	}
+	if usable(all) {
+		return all
+	}
+	for i := 1; ; i++ {
+		if name := all + strconv.Itoa(i); usable(name) {
+			return name
+		}
+	}
+}
+
+// packValues returns comments of the constants packed into a single string,
+// or nil if their values aren't contiguous integers.
+func packValues(values []constValue) *packedValue {
+	sorted := make([]constValue, len(values))
+	copy(sorted, values)
+	for _, v := range sorted {
+		if v.val == nil || v.val.Kind() != constant.Int {
+			return nil
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return constant.Compare(sorted[i].val, token.LSS, sorted[j].val)
+	})
+
+	one := constant.MakeInt64(1)
+	for i := 1; i < len(sorted); i++ {
+		next := constant.BinaryOp(sorted[i-1].val, token.ADD, one)
+		if !constant.Compare(sorted[i].val, token.EQL, next) {
+			return nil
+		}
+	}
+
+	packed := &packedValue{
+		Index: []int{0},
+		Min:   sorted[0].val.ExactString(),
+	}
+	if constant.Sign(sorted[0].val) < 0 {
+		packed.Min = "(" + packed.Min + ")"
+	}
+	for _, v := range sorted {
+		packed.Names += v.Msg
+		packed.Index = append(packed.Index, len(packed.Names))
+	}
+
+	switch {
+	case len(packed.Names) <= math.MaxUint8:
+		packed.IndexType = "uint8"
+	case len(packed.Names) <= math.MaxUint16:
+		packed.IndexType = "uint16"
+	default:
+		packed.IndexType = "uint32"
+	}
+
+	return packed
+}
+
+// checkDuplicateMessages returns an error listing the constants sharing the same message,
+// since such constants can't be told apart by their comments.
+func checkDuplicateMessages(typeName string, values []constValue) error {
+	var msgs []string
+	names := make(map[string][]string, len(values))
+	for _, v := range values {
+		if _, ok := names[v.Msg]; !ok {
+			msgs = append(msgs, v.Msg)
+		}
+		names[v.Msg] = append(names[v.Msg], v.Name)
+	}
+
+	var conflicts []string
+	for _, msg := range msgs {
+		if len(names[msg]) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("%s have the same comment %q", strings.Join(names[msg], ", "), msg))
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("constants of type %s: %s", typeName, strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// constMessage returns the message of the named constant taken from its comments.
+// By default it is the doc comment, which must start with the constant name, or with
+// the name trimmed by TrimPrefix, unless NoNamePrefix is set. With LineComment
+// the line comment is preferred, and the constant name is optional there.
+// With NameFallback a constant without a usable comment gets its own name,
+// trimmed by TrimPrefix, unless AutoTrimPrefix trims it later.
+func (c *Config) constMessage(constName string, doc, comment *ast.CommentGroup) string {
+	if message, ok := c.Messages[constName]; ok {
+		return message
+	}
+
+	var message string
+	switch {
+	case c.LineComment && comment != nil:
+		message = c.commentMessage(constName, comment, true)
+	case doc != nil:
+		// When TrimPrefix or NoNamePrefix is set, a doc comment starting with neither name
+		// is used as a whole.
+		message = c.commentMessage(constName, doc, c.TrimPrefix != "" || c.NoNamePrefix)
+	}
+	if c.StripMarkup {
+		message = stripMarkup(message)
+	}
+
+	if message == "" && c.NameFallback && !c.AutoTrimPrefix {
+		if message = strings.TrimPrefix(constName, c.TrimPrefix); message == "" {
+			message = constName
+		}
+	}
+	return message
+}
+
+// namePrefix returns the longest common prefix of the constant names, which ends
+// before an upper case letter, a digit or an underscore in every name and leaves
+// each of them non-empty, e.g. Status of StatusOK and StatusNotFound.
+// A single name has no common prefix.
+func namePrefix(values []constValue) string {
+	if len(values) < 2 {
+		return ""
+	}
+	prefix := values[0].Name
+	for _, v := range values[1:] {
+		i := 0
+		for i < len(prefix) && i < len(v.Name) && prefix[i] == v.Name[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+
+	for ; prefix != ""; prefix = prefix[:len(prefix)-1] {
+		boundary := true
+		for _, v := range values {
+			r, _ := utf8.DecodeRuneInString(v.Name[len(prefix):])
+			if r == utf8.RuneError || !(unicode.IsUpper(r) || unicode.IsDigit(r) || r == '_') {
+				boundary = false
+				break
+			}
+		}
+		if boundary {
+			break
+		}
+	}
+	return prefix
+}
+
+// commentMessage returns the comment text collapsed into a single line, unless
+// Multiline is set, without the leading constant name. If the comment doesn't start
+// with the name, it is used as a whole when whole is set, or the message is empty otherwise.
+func (c *Config) commentMessage(constName string, cg *ast.CommentGroup, whole bool) string {
+	comment := commentText(cg)
+	if c.Multiline {
+		comment = strings.NewReplacer("\r\n", "\n", "\r", "\n").Replace(comment)
+	} else {
+		comment = joinLines(comment)
+	}
+
+	shortName := strings.TrimPrefix(constName, c.TrimPrefix)
+	var message string
+	switch {
+	case hasNamePrefix(comment, constName):
+		message = trimSeparator(strings.TrimPrefix(comment, constName))
+	case hasNamePrefix(comment, shortName):
+		message = trimSeparator(strings.TrimPrefix(comment, shortName))
+	case whole:
+		message = comment
+	}
+
+	return strings.TrimSpace(message)
+}
+
+// trimSeparator returns the rest of the comment after the name without
+// the separator written between them, if any, e.g. "Not Found" of ": Not Found".
+// A hyphen is a separator only if followed by a space, so "-40" is kept.
+func trimSeparator(rest string) string {
+	rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+	switch {
+	case strings.HasPrefix(rest, ":"), strings.HasPrefix(rest, "\u2014"):
+		_, size := utf8.DecodeRuneInString(rest)
+		return rest[size:]
+	case rest == "-" || strings.HasPrefix(rest, "- "):
+		return rest[1:]
+	}
+	return rest
+}
+
+// markupTag matches HTML tags, e.g. <b>, </b> and <br/>, but not "a < b".
+var markupTag = regexp.MustCompile(`</?[A-Za-z][^<>]*>`)
+
+// markupEntities unescapes the entities of the characters HTML must escape.
+var markupEntities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+// stripMarkup returns the message without HTML tags, with the entities unescaped
+// after removing the tags, so that "&lt;b&gt;" stays as the text "<b>".
+func stripMarkup(message string) string {
+	message = markupTag.ReplaceAllString(message, "")
+	return strings.TrimSpace(markupEntities.Replace(message))
+}
+
+// joinLines returns the lines of the text joined by single spaces, whichever
+// line endings they have, with neither blank lines nor spaces around line breaks.
+func joinLines(text string) string {
+	var lines []string
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == '\r' }) {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, " ")
+}
+
+// commentText returns the text of the comment group. Unlike line comments,
+// lines of block comments keep their indentation, which is trimmed along with
+// the leading "*" if every line is decorated with it.
+func commentText(cg *ast.CommentGroup) string {
+	text := cg.Text()
+	if !strings.HasPrefix(cg.List[0].Text, "/*") {
+		return text
+	}
+
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	decorated := true
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+		if lines[i] != "" && !strings.HasPrefix(lines[i], "*") {
+			decorated = false
+		}
+	}
+	if decorated {
+		for i, line := range lines {
+			lines[i] = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+		}
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// hasNamePrefix reports whether the comment starts with the whole identifier name,
+// so that "Notice" isn't taken for the name "Not".
+func hasNamePrefix(comment, name string) bool {
+	if name == "" || !strings.HasPrefix(comment, name) {
+		return false
+	}
+	next, _ := utf8.DecodeRuneInString(comment[len(name):])
+	return next == utf8.RuneError || !(unicode.IsLetter(next) || unicode.IsDigit(next) || next == '_')
+}
+
+// LoadTemplate parses the template file on top of the built-in templates,
+// so that the named ones like "switch" can be used in the custom template.
+func LoadTemplate(fileName string) (*template.Template, error) {
+	text, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := fileTemplate.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.New(filepath.Base(fileName)).Parse(string(text))
+}
+
+// LoadMessages returns the messages by constant names read from the CSV file,
+// which has a row of a constant name and its message for each constant.
+func LoadMessages(fileName string) (map[string]string, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = 2
+	messages := make(map[string]string)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return messages, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+		name := strings.TrimSpace(record[0])
+		if !token.IsIdentifier(name) {
+			return nil, fmt.Errorf("%s:%d: invalid constant name %q", fileName, line, name)
+		}
+		if _, ok := messages[name]; ok {
+			return nil, fmt.Errorf("%s:%d: duplicate message of constant %s", fileName, line, name)
+		}
+		messages[name] = record[1]
+	}
+}
+
+// LoadHeader returns the header comment given either by a file name or by the text itself.
+// Lines which are not comments yet are commented out.
+func LoadHeader(value string) (string, error) {
+	text := value
+	if info, err := os.Stat(value); err == nil && info.Mode().IsRegular() {
+		data, err := ioutil.ReadFile(value)
+		if err != nil {
+			return "", err
+		}
+		text = string(data)
+	}
+
+	text = strings.TrimRight(text, "\r\n")
+	if strings.HasPrefix(strings.TrimSpace(text), "/*") {
+		return text, nil
+	}
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(line), "//"):
+			lines[i] = line
+		case strings.TrimSpace(line) == "":
+			lines[i] = "//"
+		default:
+			lines[i] = "// " + line
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// render executes the template for the named file and returns the formatted source.
+func (c *Config) render(fileName string, fileTemplate *template.Template, tmplData interface{}) ([]byte, error) {
+	buf := bytes.Buffer{}
+	if err := fileTemplate.Execute(&buf, tmplData); err != nil {
+		return nil, err
+	}
+
+	var fmtSource []byte
+	var err error
+	if c.Format == "goimports" {
+		// Imports are added, removed and sorted the same way goimports does,
+		// which is handy for custom templates.
+		srcName := fileName
+		if srcName == StdoutName {
+			srcName = ""
+		}
+		fmtSource, err = imports.Process(srcName, buf.Bytes(), nil)
+	} else {
+		fmtSource, err = format.Source(buf.Bytes())
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Names of the packages a custom template imports can't be told from their paths.
+	if fileTemplate != c.Template {
+		if err := checkImports(fmtSource); err != nil {
+			return nil, err
+		}
+	}
+	return fmtSource, nil
+}
+
+// IsGenerated reports whether the file content has a generated marker,
+// which is a whole comment line before the package clause.
+func IsGenerated(src []byte) bool {
+	for _, line := range strings.Split(string(src), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "package ") {
+			break
+		}
+		if generatedMarker.MatchString(line) || line == legacyMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// checkImports returns an error if the source imports a package it doesn't use,
+// taking the last element of the path as the package name unless it's named explicitly.
+func checkImports(src []byte) error {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				used[ident.Name] = true
+			}
+		}
+		return true
+	})
+
+	for _, spec := range f.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return err
+		}
+		name := path.Base(importPath)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		if name == "_" || name == "." {
+			continue
+		}
+		if !used[name] {
+			return fmt.Errorf("generated code imports %q but doesn't use it", importPath)
+		}
+	}
+	return nil
+}
+
+// filePackage returns the package of the loaded ones the named file belongs to.
+func filePackage(pkgs map[string]*packages.Package, fileName string) (map[string]*packages.Package, error) {
+	for name, pkg := range pkgs {
+		for _, f := range pkg.Syntax {
+			if pkg.Fset.Position(f.Pos()).Filename == fileName {
+				return map[string]*packages.Package{name: pkg}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("file %s isn't loaded, it may be excluded by build constraints", fileName)
+}
+
+// loadPackages loads and type checks the packages in the directory,
+// keyed by package name. Only files matching build constraints are loaded,
+// the same way go build does. Test files are skipped unless asked,
+// as they usually aren't the place for generated methods.
+func (c *Config) loadPackages(dir string) (map[string]*packages.Package, error) {
+	config := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+			packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:   dir,
+		Tests: c.IncludeTests,
+	}
+	if len(c.Tags) > 0 {
+		config.BuildFlags = []string{"-tags=" + strings.Join(c.Tags, ",")}
+	}
+
+	list, err := packages.Load(config, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	pkgs := make(map[string]*packages.Package, len(list))
+	for _, pkg := range list {
+		// With tests, a package is loaded both with and without its test files,
+		// and along with the generated test main package, which is of no use here.
+		if strings.HasSuffix(pkg.PkgPath, ".test") {
+			continue
+		}
+		if prev, ok := pkgs[pkg.Name]; ok && len(prev.Syntax) >= len(pkg.Syntax) {
+			continue
+		}
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("checking package: %v", pkg.Errors[0])
+		}
+		pkgs[pkg.Name] = pkg
+	}
+	if len(pkgs) == 0 {
+		return nil, fmt.Errorf("no packages found")
+	}
+	return pkgs, nil
+}
@@ -0,0 +1,464 @@
+package generator
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// TestGolden compares the code generated for the packages in testdata
+// with their golden files, which are rewritten with -update.
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"int", Config{Dir: "testdata/status", Types: []string{"StatusCode"}, Parse: true}},
+		{"string", Config{Dir: "testdata/color", Types: []string{"Color"}, Values: true}},
+		{"iota", Config{Dir: "testdata/weekday", Types: []string{"Weekday"}, IsValid: true}},
+		{"assert", Config{Dir: "testdata/weekday", Types: []string{"Weekday"}, Assert: true,
+			GoString: true, JSON: true, SQL: true, Text: true, XML: true}},
+		{"crlf", Config{Dir: "testdata/crlf", Types: []string{"Newline"}, Checked: true}},
+		{"escape", Config{Dir: "testdata/escape", Types: []string{"Sign"}, Parse: true, Registry: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src, err := Generate(test.cfg)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			golden := filepath.Join(test.cfg.Dir, test.name+".golden")
+			if *update {
+				if err := ioutil.WriteFile(golden, src, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(src, expected) {
+				t.Fatalf("Generated code is incorrect\nExpected: %s\nObtained: %s", expected, src)
+			}
+		})
+	}
+}
+
+// TestGenerateStable checks that generating the same package again gives the same bytes.
+func TestGenerateStable(t *testing.T) {
+	cfg := Config{Dir: "testdata/escape", Types: []string{"Sign"}, Parse: true, Registry: true, Values: true, Map: true}
+	first, err := Generate(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := Generate(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Fatalf("Generated code differs\nFirst: %s\nSecond: %s", first, second)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	data := map[string]string{
+		`Say "hi"`:           `"Say \"hi\""`,
+		`C:\Users`:           `"C:\\Users"`,
+		"Left\tRight":        `"Left\tRight"`,
+		"Café 日本語 🚀":         `"Café 日本語 🚀"`,
+		"No\u00a0break":      `"No\u00a0break"`,
+		"Zero\u200bwidth":    `"Zero\u200bwidth"`,
+		"Bell\x07\x7f\u0085": `"Bell\a\x7f\u0085"`,
+		"Invalid\xff":        `"Invalid\xff"`,
+	}
+
+	for s, expected := range data {
+		t.Run(expected, func(t *testing.T) {
+			quoted := quote(s)
+			if quoted != expected {
+				t.Fatalf("Quoted string is incorrect\nExpected: %s\nObtained: %s", expected, quoted)
+			}
+			if unquoted, err := strconv.Unquote(quoted); err != nil || unquoted != s {
+				t.Fatalf("Quoted string %s is unquoted incorrectly: %q, %v", quoted, unquoted, err)
+			}
+		})
+	}
+}
+
+func TestCheckImports(t *testing.T) {
+	data := map[string]string{
+		`import "strings"; var _ = strings.ToLower`:              "",
+		`import str "strings"; var _ = str.ToLower`:              "",
+		`import _ "embed"`:                                       "",
+		`import . "strings"; var _ = ToLower`:                    "",
+		`import "strings"`:                                       `generated code imports "strings" but doesn't use it`,
+		`import str "strings"; var _ = strings.ToLower`:          `generated code imports "strings" but doesn't use it`,
+		`import ("fmt"; "strings"); var _ = fmt.Sprint`:          `generated code imports "strings" but doesn't use it`,
+		`import yaml "gopkg.in/yaml.v3"; var _ = yaml.Unmarshal`: "",
+	}
+
+	for decls, expected := range data {
+		t.Run(decls, func(t *testing.T) {
+			err := checkImports([]byte("package p; " + decls))
+			if (expected == "" && err != nil) || (expected != "" && (err == nil || err.Error() != expected)) {
+				t.Fatalf("Error is incorrect\nExpected: %s\nObtained: %v", expected, err)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate(Config{Dir: "../color", Types: []string{"Color"}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{
+		"// Code generated by cmtstringer. DO NOT EDIT.",
+		"func (c Color) String() string {",
+		`return "Fire Engine Red"`,
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Fatalf("Generated code is incorrect\nExpected: %s\nObtained: %s", expected, src)
+		}
+	}
+}
+
+func TestGenerateEmptyDefault(t *testing.T) {
+	empty := ""
+	src, err := Generate(Config{Dir: "../color", Types: []string{"Color"}, Default: &empty})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "default:\n\t\treturn \"\"\n"; !strings.Contains(string(src), expected) {
+		t.Fatalf("Generated code is incorrect\nExpected: %s\nObtained: %s", expected, src)
+	}
+}
+
+func TestHasVerb(t *testing.T) {
+	data := map[string]bool{
+		"Unknown":          false,
+		"100% unknown":     false,
+		"100%":             false,
+		"100%% unknown %d": true,
+		"100%%d":           false,
+		"unknown %d":       true,
+		"unknown %-5v":     true,
+		"unknown %#x":      true,
+		"%q unknown":       true,
+	}
+
+	for msg, expected := range data {
+		t.Run(msg, func(t *testing.T) {
+			if verb := hasVerb(msg); verb != expected {
+				t.Fatalf("Verb is incorrect\nExpected: %v\nObtained: %v", expected, verb)
+			}
+		})
+	}
+}
+
+func TestGenerateDefaultFormat(t *testing.T) {
+	data := map[string]string{
+		"100% unknown": `return "100% unknown"`,
+		"unknown %d":   `return fmt.Sprintf("unknown %d", raw(c))`,
+	}
+
+	for msg, expected := range data {
+		t.Run(msg, func(t *testing.T) {
+			msg := msg
+			src, err := Generate(Config{Dir: "../color", Types: []string{"Color"}, Default: &msg})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(string(src), expected) {
+				t.Fatalf("Generated code is incorrect\nExpected: %s\nObtained: %s", expected, src)
+			}
+		})
+	}
+}
+
+func TestGenerateEmptyBitmaskZero(t *testing.T) {
+	empty := ""
+	src, err := Generate(Config{Dir: "../perm", Types: []string{"Perm"}, Bitmask: true, BitmaskZero: &empty})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := "case 0:\n\t\treturn \"\"\n"; !strings.Contains(string(src), expected) {
+		t.Fatalf("Generated code is incorrect\nExpected: %s\nObtained: %s", expected, src)
+	}
+}
+
+func TestGenerateFilesWithTest(t *testing.T) {
+	files, err := GenerateFiles(Config{Dir: "../color", Types: []string{"Color"}, Test: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(files) != 2 || !strings.HasSuffix(files[1].Name, "color_string_gen_test.go") {
+		t.Fatalf("Generated files are incorrect: %d files", len(files))
+	}
+
+	if _, err := Generate(Config{Dir: "../color", Types: []string{"Color"}, Test: true}); err == nil {
+		t.Fatal("Expected error for several generated files")
+	}
+}
+
+func TestGenerateFilesPerTag(t *testing.T) {
+	files, err := GenerateFiles(Config{Dir: "../platform", Types: []string{"Platform"}, Tags: []string{"alpha", "beta"}, PerTag: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []struct{ name, build string }{
+		{"platform_string_gen_alpha.go", "//go:build alpha\n"},
+		{"platform_string_gen_beta.go", "//go:build beta\n"},
+		{"platform_string_gen.go", "//go:build !alpha && !beta\n"},
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("Generated files are incorrect: %d files", len(files))
+	}
+	for i, file := range files {
+		if filepath.Base(file.Name) != expected[i].name || !bytes.HasPrefix(file.Source, []byte(expected[i].build)) {
+			t.Fatalf("Generated file %s is incorrect\nExpected: %s\nObtained: %s", file.Name, expected[i].build, file.Source)
+		}
+	}
+}
+
+func TestGenerateFilesAll(t *testing.T) {
+	files, err := GenerateFiles(Config{Dir: "../pizza", All: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(files) != 2 || filepath.Base(files[0].Name) != "size_string_gen.go" || filepath.Base(files[1].Name) != "topping_string_gen.go" {
+		t.Fatalf("Generated files are incorrect: %d files", len(files))
+	}
+}
+
+func TestLoadMessages(t *testing.T) {
+	messages, err := LoadMessages("../greeting/de.csv")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(messages) != 3 || messages["GreetingThanks"] != "Danke, vielen Dank" {
+		t.Fatalf("Messages are incorrect: %v", messages)
+	}
+
+	if _, err := LoadMessages("testdata/messages.csv"); err == nil ||
+		err.Error() != "testdata/messages.csv:2: duplicate message of constant Red" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestGenerateFilesCompat(t *testing.T) {
+	files, err := GenerateFiles(Config{Dir: "../platform", Types: []string{"Platform"}, Tags: []string{"alpha", "beta"},
+		PerTag: true, Compat: "go1.16"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := "//go:build !alpha && !beta\n// +build !alpha,!beta\n\n"
+	if len(files) != 3 || !bytes.HasPrefix(files[2].Source, []byte(expected)) {
+		t.Fatalf("Generated files are incorrect\nExpected: %s\nObtained: %s", expected, files[2].Source)
+	}
+
+	for _, compat := range []string{"1.16", "go1.1"} {
+		if _, err := Generate(Config{Dir: "../color", Types: []string{"Color"}, Compat: compat}); err == nil {
+			t.Fatalf("Expected error for Go version %s", compat)
+		}
+	}
+}
+
+func TestGenerateUndeclaredType(t *testing.T) {
+	_, err := Generate(Config{Dir: "../color", Types: []string{"Colour"}})
+	if err == nil || err.Error() != `no declared type "Colour" found` {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestGenerateSeveralDefaults(t *testing.T) {
+	_, err := Generate(Config{Dir: "testdata/twodefaults", Types: []string{"Level"}})
+	if err == nil || err.Error() != "constants LevelNone and LevelUnset of type Level are both marked //cmtstringer:default" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestGenerateForeignType(t *testing.T) {
+	_, err := Generate(Config{Dir: "testdata/holiday", Types: []string{"Weekday"}})
+	if err == nil || !strings.HasPrefix(err.Error(), "type Weekday isn't declared in package holiday") ||
+		!strings.HasSuffix(err.Error(), "holiday.go:7:2 declares a constant of type time.Weekday: methods must be generated in the package declaring the type") {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestGenerateFlagValueMethod(t *testing.T) {
+	_, err := Generate(Config{Dir: "../color", Types: []string{"Color"}, FlagValue: true, Method: "Label"})
+	if err == nil || err.Error() != "flag.Value requires String, it can't be generated along with method Label" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestGenerateTemplateImports(t *testing.T) {
+	tmpl := template.Must(template.New("yaml").Parse("package {{.PackageName}}\n\nimport \"gopkg.in/yaml.v3\"\n\nvar _ = yaml.Marshal\n"))
+	if _, err := Generate(Config{Dir: "../color", Types: []string{"Color"}, Template: tmpl}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestGenerateMsgPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected []string
+	}{
+		{"description", Config{Dir: "../currency", Types: []string{"Currency"}, TrimPrefix: "Currency", Description: true, MsgPrefix: "FX:"},
+			[]string{`const _Currency_name = "USDEURJPY"`, `return "FX: Euro"`}},
+		{"locales", Config{Dir: "../weather", Types: []string{"Weather"}, MsgPrefix: "W:",
+			Locales: map[string]map[string]string{"de": {"WeatherSunny": "Sonnig"}}},
+			[]string{`"W: Sunny`, `WeatherSunny: "W: Sonnig"`}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			src, err := Generate(test.cfg)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for _, expected := range test.expected {
+				if !strings.Contains(string(src), expected) {
+					t.Fatalf("Generated code is incorrect\nExpected: %s\nObtained: %s", expected, src)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateReceiver(t *testing.T) {
+	tmpl, err := LoadTemplate("../unit/unit.tmpl")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data := map[string]string{
+		"":   "func (u1 Unit) String() string {",
+		"un": "func (un Unit) String() string {",
+	}
+
+	for receiver, expected := range data {
+		t.Run(expected, func(t *testing.T) {
+			src, err := Generate(Config{Dir: "../unit", Types: []string{"Unit"}, Template: tmpl, Receiver: receiver})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(string(src), expected) {
+				t.Fatalf("Generated code is incorrect\nExpected: %s\nObtained: %s", expected, src)
+			}
+		})
+	}
+
+	for _, receiver := range []string{"1x", "type", "_", "u v"} {
+		_, err := Generate(Config{Dir: "../unit", Types: []string{"Unit"}, Receiver: receiver})
+		if err == nil || err.Error() != fmt.Sprintf("invalid receiver name %q", receiver) {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func TestGenerateValidateVerb(t *testing.T) {
+	tests := []struct {
+		dir, typeName, expected string
+	}{
+		{"../access", "Access", `return fmt.Errorf("invalid Access: %d", raw(a))`},
+		{"../color", "Color", `return fmt.Errorf("invalid Color: %q", raw(c))`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.typeName, func(t *testing.T) {
+			src, err := Generate(Config{Dir: test.dir, Types: []string{test.typeName}, Validate: true})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(string(src), test.expected) {
+				t.Fatalf("Generated code is incorrect\nExpected: %s\nObtained: %s", test.expected, src)
+			}
+		})
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	data := map[string]bool{
+		"// Code generated by cmtstringer. DO NOT EDIT.\n\npackage p\n":                     true,
+		"// Code generated by cmtstringer. DO NOT EDIT.\r\n\r\npackage p\r\n":               true,
+		"//go:build alpha\n\n// Code generated by cmtstringer. DO NOT EDIT.\n\npackage p\n": true,
+		"// Copyright\n\n// Code generated by stringer. DO NOT EDIT.\n\npackage p\n":        true,
+		"// This file is generated by command cmtstringer.\n\npackage p\n":                  true,
+		"package p\n": false,
+		"package p\n\n// Code generated by cmtstringer. DO NOT EDIT.\n":                    false,
+		"package p\n\nconst marker = \"// Code generated by cmtstringer. DO NOT EDIT.\"\n": false,
+		"// Code generated by cmtstringer. DO NOT EDIT. Or do.\n\npackage p\n":             false,
+		"/* Code generated by cmtstringer. DO NOT EDIT. */\n\npackage p\n":                 false,
+	}
+
+	for src, expected := range data {
+		t.Run(src, func(t *testing.T) {
+			if generated := IsGenerated([]byte(src)); generated != expected {
+				t.Fatalf("Generated is incorrect\nExpected: %v\nObtained: %v", expected, generated)
+			}
+		})
+	}
+}
+
+func TestGenerateIntegerOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      Config
+		expected string
+	}{
+		{"int", Config{Dir: "../color", Types: []string{"Color"}, Int: true}, "type Color of Int method isn't of an integer type"},
+		{"guard", Config{Dir: "../color", Types: []string{"Color"}, Guard: true}, "guarded type Color isn't of an integer type"},
+		{"bitmask", Config{Dir: "../color", Types: []string{"Color"}, Bitmask: true}, "bitmask type Color isn't of an integer type"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Generate(test.cfg)
+			if err == nil || err.Error() != test.expected {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateMethodClash(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"Index", Config{Index: true}},
+		{"Description", Config{Description: true}},
+		{"Int", Config{Int: true}},
+		{"MarshalJSON", Config{JSON: true}},
+		{"Value", Config{SQL: true}},
+		{"StringIn", Config{Locales: map[string]map[string]string{"de": {}}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := test.cfg
+			cfg.Dir, cfg.Types, cfg.Method = "../weekday", []string{"Weekday"}, test.name
+			_, err := Generate(cfg)
+			if expected := "method " + test.name + " is generated by another option, it can't return comments"; err == nil || err.Error() != expected {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		})
+	}
+
+	if _, err := Generate(Config{Dir: "../weekday", Types: []string{"Weekday"}, Method: "Index"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
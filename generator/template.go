@@ -0,0 +1,629 @@
+package generator
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+const (
+	fileTemplateStr = `{{with .Build}}//go:build {{.}}
+{{range $.PlusBuild}}{{.}}
+{{end}}
+{{end}}{{with .Header}}{{.}}
+
+{{end}}// Code generated by cmtstringer. DO NOT EDIT.
+// Types: {{template "typenames" .}}
+
+package {{.PackageName}}
+{{if .Imports}}
+import (
+{{range .Imports}}	{{quote .}}
+{{end}})
+{{end}}{{range .Types}}
+{{if .Bitmask}}{{template "bitmask" .}}{{else if .Map}}{{template "map" .}}{{else if .Packed}}{{template "array" .}}{{else}}{{template "switch" .}}{{end}}
+{{- if .Desc}}{{template "description" .}}{{end}}
+{{- if .Parse}}{{template "parse" .}}{{end}}
+{{- if .JSON}}{{template "json" .}}{{end}}
+{{- if .SQL}}{{template "sql" .}}{{end}}
+{{- if .Text}}{{template "text" .}}{{end}}
+{{- if .FlagValue}}{{template "flagvalue" .}}{{end}}
+{{- if .YAML}}{{template "yaml" .}}{{end}}
+{{- if .XML}}{{template "xml" .}}{{end}}
+{{- if .IsValid}}{{template "isvalid" .}}{{end}}
+{{- if .Validate}}{{template "validate" .}}{{end}}
+{{- if .Checked}}{{template "checked" .}}{{end}}
+{{- if .Values}}{{template "values" .}}{{end}}
+{{- if .GoString}}{{template "gostring" .}}{{end}}
+{{- if .Append}}{{template "append" .}}{{end}}
+{{- if .Int}}{{template "int" .}}{{end}}
+{{- if .Index}}{{template "index" .}}{{end}}
+{{- if .Registry}}{{template "registry" .}}{{end}}
+{{- if .StringIn}}{{template "stringin" .}}{{end}}
+{{- if .Assert}}{{template "assert" .}}{{end}}
+{{- if .Guard}}{{template "guard" .}}{{end}}
+{{- end}}`
+
+	switchTemplateStr = `{{define "switch"}}
+// {{.Method}} returns comment of const type {{.TypeName}}
+func ({{template "recv" .}}) {{.Method}}() string {
+	switch {{template "val" .}} {
+	{{range .Consts}}case {{.Name}}:
+		return {{quote .Msg}}
+	{{end}}{{if .NoDefault}}}
+	{{template "default" .}}{{else}}default:
+		{{template "default" .}}
+	}{{end}}
+}
+{{end}}`
+
+	mapTemplateStr = `{{define "map"}}
+// _{{.TypeName}}_map maps consts of type {{.TypeName}} to their comments
+var _{{.TypeName}}_map = map[{{.TypeName}}]string{
+	{{range .Consts}}{{.Name}}: {{quote .Msg}},
+	{{end}}
+}
+
+// {{.Method}} returns comment of const type {{.TypeName}}
+func ({{template "recv" .}}) {{.Method}}() string {
+	if str, ok := _{{.TypeName}}_map[{{template "val" .}}]; ok {
+		return str
+	}
+	{{template "default" .}}
+}
+{{end}}`
+
+	arrayTemplateStr = `{{define "array"}}
+const _{{.TypeName}}_name = {{quote .Packed.Names}}
+
+var _{{.TypeName}}_index = [...]{{.Packed.IndexType}}{ {{- range $i, $v := .Packed.Index}}{{if $i}}, {{end}}{{$v}}{{end -}} }
+
+// {{.Method}} returns comment of const type {{.TypeName}}
+func ({{template "recv" .}}) {{.Method}}() string {
+	idx := {{template "val" .}}{{if ne .Packed.Min "0"}} - {{.Packed.Min}}{{end}}
+	if uint64(idx) >= uint64(len(_{{.TypeName}}_index)-1) {
+		{{template "default" .}}
+	}
+	return _{{.TypeName}}_name[_{{.TypeName}}_index[idx]:_{{.TypeName}}_index[idx+1]]
+}
+{{end}}`
+
+	// Bits are checked from the first one, so the comments are joined in their order.
+	bitmaskTemplateStr = `{{define "bitmask"}}
+// {{.Method}} returns comment of const type {{.TypeName}}, or the comments of its set bits joined by "|"
+func ({{template "recv" .}}) {{.Method}}() string {
+	switch {{template "val" .}} {
+	{{range .Consts}}case {{.Name}}:
+		return {{quote .Msg}}
+	{{end}}
+	{{- if not .Bitmask.HasZero}}case 0:
+		return {{quote .Bitmask.Zero}}
+	{{end}}}
+
+	var buf []byte
+	rest := {{template "val" .}}
+	{{- range .Bitmask.Bits}}
+	if rest&{{.Name}} != 0 {
+		buf = append(buf, {{quote (print "|" .Msg)}}...)
+		rest &^= {{.Name}}
+	}
+	{{- end}}
+	if rest != 0 || len(buf) == 0 {
+		{{template "default" .}}
+	}
+	return string(buf[1:])
+}
+{{end}}`
+
+	descriptionTemplateStr = `{{define "description"}}
+// Description returns comment of const type {{.TypeName}}, while {{.Method}} returns its name
+func ({{template "recv" .}}) Description() string {
+	switch {{template "val" .}} {
+	{{range .Consts}}case {{.Name}}:
+		return {{quote .Desc}}
+	{{end}}default:
+		return {{.Receiver}}.{{.Method}}()
+	}
+}
+{{end}}`
+
+	defaultTemplateStr = `{{define "default"}}
+	{{- if .DefaultPanic}}type raw {{.TypeName}}
+	panic(fmt.Errorf("invalid {{.TypeName}}: {{.Verb}}", raw({{template "val" .}})))
+	{{- else if .DefaultFormat}}type raw {{.TypeName}}
+	return fmt.Sprintf({{quote .Default}}, raw({{template "val" .}}))
+	{{- else}}return {{quote .Default}}{{end}}
+{{- end}}`
+
+	parseTemplateStr = `{{define "parse"}}
+// Parse{{.TypeName}} returns const of type {{.TypeName}} by its comment
+func Parse{{.TypeName}}(s string) ({{.TypeName}}, error) {
+	switch {{if .CaseInsensitive}}strings.ToLower(s){{else}}s{{end}} {
+	{{range .Consts}}case {{if $.CaseInsensitive}}{{quote (lower .Msg)}}{{else}}{{quote .Msg}}{{end}}
+		{{- range .Aliases}}, {{if $.CaseInsensitive}}{{quote (lower .Msg)}}{{else}}{{quote .Msg}}{{end}}{{end}}:
+		return {{.Name}}, nil
+	{{end}}}
+	var zero {{.TypeName}}
+	return zero, fmt.Errorf("unknown {{.TypeName}} %q", s)
+}
+{{end}}`
+
+	jsonTemplateStr = `{{define "json"}}
+// MarshalJSON implements json.Marshaler interface for type {{.TypeName}}
+func ({{template "recv" .}}) MarshalJSON() ([]byte, error) {
+	switch {{template "val" .}} {
+	case {{template "names" .}}:
+		return json.Marshal({{.Receiver}}.{{.Method}}())
+	}
+	type raw {{.TypeName}}
+	return json.Marshal(raw({{template "val" .}}))
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface for type {{.TypeName}}
+func ({{.Receiver}} *{{.TypeName}}) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("invalid {{.TypeName}} %s: %v", data, err)
+	}
+	val, err := Parse{{.TypeName}}(str)
+	if err != nil {
+		return err
+	}
+	*{{.Receiver}} = val
+	return nil
+}
+{{end}}`
+
+	sqlTemplateStr = `{{define "sql"}}
+// Value implements driver.Valuer interface for type {{.TypeName}}
+func ({{template "recv" .}}) Value() (driver.Value, error) {
+	switch {{template "val" .}} {
+	case {{template "names" .}}:
+		return {{.Receiver}}.{{.Method}}(), nil
+	}
+	type raw {{.TypeName}}
+	return nil, fmt.Errorf("invalid {{.TypeName}} {{.Verb}}", raw({{template "val" .}}))
+}
+
+// Scan implements sql.Scanner interface for type {{.TypeName}}
+func ({{.Receiver}} *{{.TypeName}}) Scan(src interface{}) error {
+	var str string
+	switch src := src.(type) {
+	case nil:
+		var zero {{.TypeName}}
+		*{{.Receiver}} = zero
+		return nil
+	case string:
+		str = src
+	case []byte:
+		str = string(src)
+	default:
+		return fmt.Errorf("invalid {{.TypeName}} source of type %T", src)
+	}
+	val, err := Parse{{.TypeName}}(str)
+	if err != nil {
+		return err
+	}
+	*{{.Receiver}} = val
+	return nil
+}
+{{end}}`
+
+	textTemplateStr = `{{define "text"}}
+// MarshalText implements encoding.TextMarshaler interface for type {{.TypeName}}
+func ({{template "recv" .}}) MarshalText() ([]byte, error) {
+	switch {{template "val" .}} {
+	case {{template "names" .}}:
+		return []byte({{.Receiver}}.{{.Method}}()), nil
+	}
+	type raw {{.TypeName}}
+	return nil, fmt.Errorf("invalid {{.TypeName}} {{.Verb}}", raw({{template "val" .}}))
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler interface for type {{.TypeName}}
+func ({{.Receiver}} *{{.TypeName}}) UnmarshalText(text []byte) error {
+	val, err := Parse{{.TypeName}}(string(text))
+	if err != nil {
+		return err
+	}
+	*{{.Receiver}} = val
+	return nil
+}
+{{end}}`
+
+	// Set is defined along with the comment method, so a flag.Value
+	// is asserted regardless of -assert.
+	flagValueTemplateStr = `{{define "flagvalue"}}
+// Set implements flag.Value interface for type {{.TypeName}}
+func ({{.Receiver}} *{{.TypeName}}) Set(str string) error {
+	val, err := Parse{{.TypeName}}(str)
+	if err != nil {
+		return err
+	}
+	*{{.Receiver}} = val
+	return nil
+}
+
+// Compile-time assertion that type {{.TypeName}} implements flag.Value interface
+var _ flag.Value = (*{{.TypeName}})(nil)
+{{end}}`
+
+	yamlTemplateStr = `{{define "yaml"}}
+// MarshalYAML implements yaml.Marshaler interface for type {{.TypeName}}
+func ({{template "recv" .}}) MarshalYAML() (interface{}, error) {
+	switch {{template "val" .}} {
+	case {{template "names" .}}:
+		return {{.Receiver}}.{{.Method}}(), nil
+	}
+	type raw {{.TypeName}}
+	return nil, fmt.Errorf("invalid {{.TypeName}} {{.Verb}}", raw({{template "val" .}}))
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface for type {{.TypeName}}
+func ({{.Receiver}} *{{.TypeName}}) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err != nil {
+		return err
+	}
+	val, err := Parse{{.TypeName}}(str)
+	if err != nil {
+		return err
+	}
+	*{{.Receiver}} = val
+	return nil
+}
+{{end}}`
+
+	xmlTemplateStr = `{{define "xml"}}
+// MarshalXML implements xml.Marshaler interface for type {{.TypeName}}
+func ({{template "recv" .}}) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	switch {{template "val" .}} {
+	case {{template "names" .}}:
+		return e.EncodeElement({{.Receiver}}.{{.Method}}(), start)
+	}
+	type raw {{.TypeName}}
+	return fmt.Errorf("invalid {{.TypeName}} {{.Verb}}", raw({{template "val" .}}))
+}
+
+// UnmarshalXML implements xml.Unmarshaler interface for type {{.TypeName}}
+func ({{.Receiver}} *{{.TypeName}}) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var str string
+	if err := d.DecodeElement(&str, &start); err != nil {
+		return err
+	}
+	val, err := Parse{{.TypeName}}(str)
+	if err != nil {
+		return err
+	}
+	*{{.Receiver}} = val
+	return nil
+}
+{{end}}`
+
+	isValidTemplateStr = `{{define "isvalid"}}
+// IsValid reports whether {{.Receiver}} is a declared const of type {{.TypeName}}
+func ({{template "recv" .}}) IsValid() bool {
+	switch {{template "val" .}} {
+	case {{template "names" .}}:
+		return true
+	default:
+		return false
+	}
+}
+{{end}}`
+
+	validateTemplateStr = `{{define "validate"}}
+// Validate returns an error if {{.Receiver}} isn't a declared const of type {{.TypeName}}
+func ({{template "recv" .}}) Validate() error {
+	switch {{template "val" .}} {
+	case {{template "names" .}}:
+		return nil
+	}
+	type raw {{.TypeName}}
+	return fmt.Errorf("invalid {{.TypeName}}: {{.Verb}}", raw({{template "val" .}}))
+}
+{{end}}`
+
+	checkedTemplateStr = `{{define "checked"}}
+// StringOK returns comment of const type {{.TypeName}},
+// and reports whether {{.Receiver}} is a declared const
+func ({{template "recv" .}}) StringOK() (string, bool) {
+	switch {{template "val" .}} {
+	{{range .Consts}}case {{.Name}}:
+		return {{quote .Msg}}, true
+	{{end}}default:
+		return "", false
+	}
+}
+{{end}}`
+
+	valuesTemplateStr = `{{define "values"}}
+// {{.TypeName}}Values returns all declared consts of type {{.TypeName}}
+func {{.TypeName}}Values() []{{.TypeName}} {
+	return []{{.TypeName}}{
+		{{range .Consts}}{{.Name}},
+		{{end}}
+	}
+}
+
+// {{.TypeName}}Strings returns comments of all declared consts of type {{.TypeName}}
+func {{.TypeName}}Strings() []string {
+	return []string{
+		{{range .Consts}}{{quote .Msg}},
+		{{end}}
+	}
+}
+{{end}}`
+
+	goStringTemplateStr = `{{define "gostring"}}
+// GoString implements fmt.GoStringer interface for type {{.TypeName}}
+func ({{template "recv" .}}) GoString() string {
+	switch {{template "val" .}} {
+	{{range .Consts}}case {{.Name}}:
+		return "{{$.PackageName}}.{{.Name}}"
+	{{end}}default:
+		type raw {{.TypeName}}
+		return fmt.Sprintf("{{.PackageName}}.{{.TypeName}}(%#v)", raw({{template "val" .}}))
+	}
+}
+{{end}}`
+
+	testFileTemplateStr = `{{with .Build}}//go:build {{.}}
+{{range $.PlusBuild}}{{.}}
+{{end}}
+{{end}}{{with .Header}}{{.}}
+
+{{end}}// Code generated by cmtstringer. DO NOT EDIT.
+// Types: {{template "typenames" .}}
+
+package {{.PackageName}}
+
+import "testing"
+{{range .Types}}
+func Test{{.TypeName}}String(t *testing.T) {
+	values := {{.TypeName}}Values()
+	messages := []string{
+		{{range .Consts}}{{quote .Msg}},
+		{{end}}
+	}
+	if len(values) != len(messages) {
+		t.Fatalf("{{.TypeName}}Values returns %d consts instead of %d", len(values), len(messages))
+	}
+
+	for i, val := range values {
+		if msg := val.{{.Method}}(); msg != messages[i] {
+			t.Errorf("{{.TypeName}} message is incorrect\nExpected: %s\nObtained: %s", messages[i], msg)
+		}
+		{{- if .Parse}}
+		parsed, err := Parse{{.TypeName}}(val.{{.Method}}())
+		if err != nil {
+			t.Errorf("parsing %q: %v", val.{{.Method}}(), err)
+		} else if parsed != val {
+			t.Errorf("{{.TypeName}} %q is parsed incorrectly\nExpected: %v\nObtained: %v", val.{{.Method}}(), val, parsed)
+		}
+		{{- end}}
+	}
+}
+{{end}}`
+
+	appendTemplateStr = `{{define "append"}}
+// Append appends comment of const type {{.TypeName}} to b and returns the extended slice
+func ({{template "recv" .}}) Append(b []byte) []byte {
+	{{- if and .Packed (not .Map)}}
+	idx := {{template "val" .}}{{if ne .Packed.Min "0"}} - {{.Packed.Min}}{{end}}
+	if uint64(idx) < uint64(len(_{{.TypeName}}_index)-1) {
+		return append(b, _{{.TypeName}}_name[_{{.TypeName}}_index[idx]:_{{.TypeName}}_index[idx+1]]...)
+	}
+	{{- end}}
+	return append(b, {{.Receiver}}.{{.Method}}()...)
+}
+{{end}}`
+
+	intTemplateStr = `{{define "int"}}
+// Int returns the value of const type {{.TypeName}} as {{.Underlying}}
+func ({{template "recv" .}}) Int() {{.Underlying}} {
+	return {{.Underlying}}({{template "val" .}})
+}
+{{end}}`
+
+	indexTemplateStr = `{{define "index"}}
+// Index returns the position of const type {{.TypeName}} in the order of declaration,
+// or -1 if {{.Receiver}} isn't declared
+func ({{template "recv" .}}) Index() int {
+	switch {{template "val" .}} {
+	{{range .Consts}}case {{.Name}}:
+		return {{.Index}}
+	{{end}}default:
+		return -1
+	}
+}
+{{end}}`
+
+	registryTemplateStr = `{{define "registry"}}
+// _{{.TypeName}}_byName maps comments to consts of type {{.TypeName}}
+var _{{.TypeName}}_byName = map[string]{{.TypeName}}{
+	{{range $c := .Consts}}{{quote .Msg}}: {{.Name}},
+	{{range .Aliases}}{{quote .Msg}}: {{$c.Name}},
+	{{end}}{{end}}
+}
+
+// _{{.TypeName}}_byValue maps consts of type {{.TypeName}} to their comments
+var _{{.TypeName}}_byValue = map[{{.TypeName}}]string{
+	{{range .Consts}}{{.Name}}: {{quote .Msg}},
+	{{end}}
+}
+
+// {{.TypeName}}ByName returns const of type {{.TypeName}} by its comment,
+// and reports whether it is found
+func {{.TypeName}}ByName(name string) ({{.TypeName}}, bool) {
+	val, ok := _{{.TypeName}}_byName[name]
+	return val, ok
+}
+
+// {{.TypeName}}ByValue returns comment of const type {{.TypeName}},
+// and reports whether it is found
+func {{.TypeName}}ByValue(val {{.TypeName}}) (string, bool) {
+	name, ok := _{{.TypeName}}_byValue[val]
+	return name, ok
+}
+{{end}}`
+
+	// Consts missing in the file of a language are missing in its map as well.
+	stringInTemplateStr = `{{define "stringin"}}
+// _{{.TypeName}}_messages maps languages to the messages of consts of type {{.TypeName}}
+var _{{.TypeName}}_messages = map[string]map[{{.TypeName}}]string{
+	{{range .Langs}}{{quote .Lang}}: {
+		{{range .Consts}}{{.Name}}: {{quote .Msg}},
+		{{end}}},
+	{{end}}
+}
+
+// StringIn returns the message of const type {{.TypeName}} in the language,
+// or the comment if there is no message in the language
+func ({{template "recv" .}}) StringIn(lang string) string {
+	if str, ok := _{{.TypeName}}_messages[lang][{{template "val" .}}]; ok {
+		return str
+	}
+	return {{.Receiver}}.{{.Method}}()
+}
+{{end}}`
+
+	guardTemplateStr = `{{define "guard"}}
+func _() {
+	// An "invalid array index" compiler error signifies that the constant values have changed.
+	// Re-run the cmtstringer command to generate them again.
+	var x [1]struct{}
+	{{- range .Consts}}
+	_ = x[{{.Name}} - {{.Value}}]
+	{{- end}}
+}
+{{end}}`
+
+	// A constant is asserted to implement the interfaces of methods on value receivers,
+	// and a nil pointer the ones of methods on pointer receivers, as all of them are with -ptr.
+	// fmt.Stringer is asserted only if the generated method is named String.
+	assertTemplateStr = `{{define "assert"}}
+{{- if or (eq .Method "String") .GoString .JSON .SQL .Text .XML}}
+// Compile-time assertions that type {{.TypeName}} implements the interfaces of its methods
+var (
+	{{- if eq .Method "String"}}
+	_ fmt.Stringer = {{template "assertval" .}}
+	{{- end}}
+	{{- if .GoString}}
+	_ fmt.GoStringer = {{template "assertval" .}}
+	{{- end}}
+	{{- if .JSON}}
+	_ json.Marshaler   = {{template "assertval" .}}
+	_ json.Unmarshaler = (*{{.TypeName}})(nil)
+	{{- end}}
+	{{- if .SQL}}
+	_ driver.Valuer = {{template "assertval" .}}
+	_ sql.Scanner   = (*{{.TypeName}})(nil)
+	{{- end}}
+	{{- if .Text}}
+	_ encoding.TextMarshaler   = {{template "assertval" .}}
+	_ encoding.TextUnmarshaler = (*{{.TypeName}})(nil)
+	{{- end}}
+	{{- if .XML}}
+	_ xml.Marshaler   = {{template "assertval" .}}
+	_ xml.Unmarshaler = (*{{.TypeName}})(nil)
+	{{- end}}
+)
+{{end}}
+{{- end}}`
+	assertValTemplateStr = `{{define "assertval"}}{{if .Ptr}}(*{{.TypeName}})(nil){{else}}{{(index .Consts 0).Name}}{{end}}{{end}}`
+
+	// Methods are defined on pointer receivers with -ptr,
+	// so the value of the receiver is referred to by "val".
+	recvTemplateStr = `{{define "recv"}}{{.Receiver}} {{if .Ptr}}*{{end}}{{.TypeName}}{{end}}`
+	valTemplateStr  = `{{define "val"}}{{if .Ptr}}*{{end}}{{.Receiver}}{{end}}`
+
+	namesTemplateStr = `{{define "names"}}{{range $i, $c := .Consts}}{{if $i}}, {{end}}{{$c.Name}}{{end}}{{end}}`
+
+	typeNamesTemplateStr = `{{define "typenames"}}{{range $i, $t := .Types}}{{if $i}}, {{end}}{{$t.TypeName}}{{end}}{{end}}`
+)
+
+var (
+	fileTemplate = template.Must(template.New("fileTemplate").Funcs(template.FuncMap{
+		"lower": strings.ToLower,
+		"quote": quote,
+	}).Parse(strings.Join([]string{
+		fileTemplateStr,
+		switchTemplateStr,
+		mapTemplateStr,
+		arrayTemplateStr,
+		bitmaskTemplateStr,
+		descriptionTemplateStr,
+		defaultTemplateStr,
+		parseTemplateStr,
+		jsonTemplateStr,
+		sqlTemplateStr,
+		textTemplateStr,
+		flagValueTemplateStr,
+		yamlTemplateStr,
+		xmlTemplateStr,
+		isValidTemplateStr,
+		validateTemplateStr,
+		checkedTemplateStr,
+		valuesTemplateStr,
+		goStringTemplateStr,
+		appendTemplateStr,
+		intTemplateStr,
+		indexTemplateStr,
+		registryTemplateStr,
+		stringInTemplateStr,
+		assertTemplateStr,
+		assertValTemplateStr,
+		guardTemplateStr,
+		recvTemplateStr,
+		valTemplateStr,
+		namesTemplateStr,
+		typeNamesTemplateStr,
+	}, "")))
+
+	testFileTemplate = template.Must(template.New("testFileTemplate").Funcs(template.FuncMap{
+		"quote": quote,
+	}).Parse(testFileTemplateStr + typeNamesTemplateStr))
+)
+
+// invisibleRunes are the runes escaped by quote besides control characters:
+// the byte order mark, which isn't allowed in Go source, and the spaces, zero width
+// and bidirectional formatting characters, which are hard to tell in a diff.
+var invisibleRunes = map[rune]bool{
+	'\u00a0': true, '\u00ad': true,
+	'\u200b': true, '\u200c': true, '\u200d': true, '\u200e': true, '\u200f': true,
+	'\u2028': true, '\u2029': true,
+	'\u202a': true, '\u202b': true, '\u202c': true, '\u202d': true, '\u202e': true,
+	'\u2060': true, '\u2066': true, '\u2067': true, '\u2068': true, '\u2069': true,
+	'\ufeff': true,
+}
+
+// quote returns the Go string literal of s. Unlike strconv.Quote, which escapes
+// the runes that aren't printable by the Unicode tables of the Go version, only
+// the fixed set of control and invisible runes is escaped, so that the generated
+// code is the same whichever Go version generates it.
+func quote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.WriteString(`\x`)
+			b.WriteString(strconv.FormatUint(uint64(s[i])|0x100, 16)[1:])
+		case r == '"' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < ' ' || r == 0x7f || r >= 0x80 && r < 0xa0 || invisibleRunes[r]:
+			// strconv.QuoteRune escapes them alike in all Go versions.
+			q := strconv.QuoteRune(r)
+			b.WriteString(q[1 : len(q)-1])
+		default:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	b.WriteByte('"')
+	return b.String()
+}
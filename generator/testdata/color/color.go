@@ -0,0 +1,13 @@
+package color
+
+// Color type of string constant
+type Color string
+
+const (
+	// Red Fire Engine Red
+	Red Color = "red"
+	// Green Forest Green
+	Green Color = "green"
+	// Blue Navy Blue
+	Blue Color = "blue"
+)
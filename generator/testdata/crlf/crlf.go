@@ -0,0 +1,19 @@
+package crlf
+
+// Newline type of constant documented with CRLF line endings
+type Newline int
+
+const (
+	// Windows Carriage return
+	// and line feed
+	Windows Newline = iota + 1
+	/* Mac Carriage return 
+	   only */
+	Mac
+	// Unix Line feed
+	Unix
+	// Mixed Line feed
+	//
+	//   and carriage return
+	Mixed
+)
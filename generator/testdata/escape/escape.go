@@ -0,0 +1,25 @@
+package escape
+
+// Sign type of constant with comments which must be escaped or not
+type Sign int
+
+const (
+	// SignQuote Say "hi"
+	SignQuote Sign = iota
+	// SignPath C:\Users
+	SignPath
+	// SignTab Left	Right
+	SignTab
+	// SignAccent Café
+	SignAccent
+	// SignCJK 日本語
+	SignCJK
+	// SignEmoji 🚀 Launch
+	SignEmoji
+	// SignSpace No break
+	SignSpace
+	// SignZeroWidth Zero​width
+	SignZeroWidth
+	// SignBidi Right‮to left
+	SignBidi
+)
@@ -0,0 +1,10 @@
+package holiday
+
+import "time"
+
+const (
+	// Saturday Day off
+	Saturday time.Weekday = 6
+	// Sunday Day off as well
+	Sunday time.Weekday = 0
+)
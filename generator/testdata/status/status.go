@@ -0,0 +1,13 @@
+package status
+
+// StatusCode type of int constant with explicit values
+type StatusCode int
+
+const (
+	// StatusOK OK
+	StatusOK StatusCode = 200
+	// StatusBadRequest Bad Request
+	StatusBadRequest StatusCode = 400
+	// StatusNotFound Not Found
+	StatusNotFound StatusCode = 404
+)
@@ -0,0 +1,14 @@
+package twodefaults
+
+// Level type of constant with several constants marked as the default case
+type Level int
+
+const (
+	// LevelNone None
+	LevelNone Level = iota //cmtstringer:default
+	// LevelLow Low
+	LevelLow
+	// LevelUnset Unset
+	//cmtstringer:default
+	LevelUnset
+)
@@ -0,0 +1,13 @@
+package weekday
+
+// Weekday type of iota constant
+type Weekday int
+
+const (
+	// Sunday First day of week
+	Sunday Weekday = iota
+	// Monday Second day of week
+	Monday
+	// Tuesday Third day of week
+	Tuesday
+)
@@ -0,0 +1,23 @@
+// Package grade is used for testing purpose only
+package grade
+
+import "strconv"
+
+//go:generate cmtstringer -type Grade -method Label -json -assert
+
+// Grade type of constant having a hand-written String method
+type Grade int
+
+const (
+	// GradeA Excellent
+	GradeA Grade = iota + 1
+	// GradeB Good
+	GradeB
+	// GradeC Satisfactory
+	GradeC
+)
+
+// String returns the number of the grade.
+func (g Grade) String() string {
+	return strconv.Itoa(int(g))
+}
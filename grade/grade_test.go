@@ -0,0 +1,48 @@
+package grade
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestGradeLabel(t *testing.T) {
+	data := map[Grade]string{
+		GradeA: "Excellent",
+		GradeB: "Good",
+		GradeC: "Satisfactory",
+		0:      "Unknown",
+	}
+
+	for grade, label := range data {
+		t.Run(label, func(t *testing.T) {
+			assertEqual(t, grade.Label(), label)
+		})
+	}
+}
+
+func TestGradeString(t *testing.T) {
+	assertEqual(t, fmt.Sprintf("%v", GradeB), "2")
+}
+
+func TestGradeJSON(t *testing.T) {
+	data, err := json.Marshal(GradeC)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertEqual(t, string(data), `"Satisfactory"`)
+
+	var grade Grade
+	if err := json.Unmarshal([]byte(`"Good"`), &grade); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if grade != GradeB {
+		t.Fatalf("Grade is unmarshaled incorrectly\nExpected: %v\nObtained: %v", GradeB, grade)
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Grade message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,18 @@
+// Package greeting is used for testing purpose only
+package greeting
+
+//go:generate cmtstringer -type Greeting -messages de.csv
+
+// Greeting type of constant having messages in a CSV file
+type Greeting int
+
+const (
+	// GreetingHello Hello
+	GreetingHello Greeting = iota
+	// GreetingGoodbye Goodbye
+	GreetingGoodbye
+	// GreetingThanks Thank you
+	GreetingThanks
+	// GreetingWelcome Welcome
+	GreetingWelcome
+)
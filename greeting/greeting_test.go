@@ -0,0 +1,24 @@
+package greeting
+
+import "testing"
+
+func TestGreeting(t *testing.T) {
+	data := map[Greeting]string{
+		GreetingHello:   "Hallo",
+		GreetingGoodbye: "Auf Wiedersehen",
+		GreetingThanks:  "Danke, vielen Dank",
+		GreetingWelcome: "Welcome",
+	}
+
+	for greeting, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, greeting.String(), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Greeting message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
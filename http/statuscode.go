@@ -1,7 +1,7 @@
 // Package http is used for testing purpose only
 package http
 
-//go:generate cmtstringer -type StatusCode
+//go:generate cmtstringer -type StatusCode -parse -case-insensitive -flagvalue -format goimports -gentest
 
 // StatusCode type of HTTP status code constant
 type StatusCode int
@@ -9,6 +9,18 @@ type StatusCode int
 const (
 	// StatusBadRequest Bad Request
 	StatusBadRequest StatusCode = 400
+	/* StatusForbidden Forbidden */
+	StatusForbidden StatusCode = 403
 	// StatusNotFound Not Found
 	StatusNotFound StatusCode = 404
+	/*
+	 * StatusMethodNotAllowed Method Not
+	 * Allowed
+	 */
+	StatusMethodNotAllowed StatusCode = 405
+	// StatusConflict Conflict
+	StatusConflict = StatusCode(409)
 )
+
+/* StatusTeapot I'm a teapot */
+const StatusTeapot StatusCode = 418
@@ -1,14 +1,20 @@
 package http
 
 import (
+	"flag"
 	"fmt"
+	"io/ioutil"
 	"testing"
 )
 
 func TestStatusCodeMessage(t *testing.T) {
 	data := map[StatusCode]string{
-		StatusBadRequest: "Bad Request",
-		StatusNotFound:   "Not Found",
+		StatusBadRequest:       "Bad Request",
+		StatusForbidden:        "Forbidden",
+		StatusNotFound:         "Not Found",
+		StatusMethodNotAllowed: "Method Not Allowed",
+		StatusConflict:         "Conflict",
+		StatusTeapot:           "I'm a teapot",
 	}
 
 	for code, msg := range data {
@@ -18,6 +24,45 @@ func TestStatusCodeMessage(t *testing.T) {
 	}
 }
 
+func TestParseStatusCode(t *testing.T) {
+	data := map[string]StatusCode{
+		"Bad Request": StatusBadRequest,
+		"Not Found":   StatusNotFound,
+		"not found":   StatusNotFound,
+		"NOT FOUND":   StatusNotFound,
+	}
+
+	for msg, code := range data {
+		t.Run(msg, func(t *testing.T) {
+			parsed, err := ParseStatusCode(msg)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			assertEqual(t, parsed.String(), code.String())
+		})
+	}
+
+	if _, err := ParseStatusCode("Unknown"); err == nil {
+		t.Fatal("Expected error for unknown message")
+	}
+}
+
+func TestStatusCodeFlag(t *testing.T) {
+	code := StatusBadRequest
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&code, "code", "status code")
+
+	if err := fs.Parse([]string{"-code", "method not allowed"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertEqual(t, code.String(), StatusMethodNotAllowed.String())
+
+	fs.SetOutput(ioutil.Discard)
+	if err := fs.Parse([]string{"-code", "Unknown"}); err == nil {
+		t.Fatal("Expected error for unknown message")
+	}
+}
+
 func assertEqual(t *testing.T, actual, expected string) {
 	if actual != expected {
 		t.Fatalf("HTTP StatusCode message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
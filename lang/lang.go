@@ -0,0 +1,25 @@
+// Package lang is used for testing purpose only
+package lang
+
+//go:generate cmtstringer -type Lang -parse -registry -dedupe
+
+// Lang type of constant having aliases of the same value
+type Lang int
+
+const (
+	// LangEnglish English
+	LangEnglish Lang = iota + 1
+	// LangGerman German
+	LangGerman
+	// LangFrench French
+	LangFrench
+)
+
+const (
+	// LangEN EN
+	LangEN = LangEnglish
+	// LangDE German
+	LangDE = LangGerman
+	// LangDeutsch Deutsch
+	LangDeutsch = LangGerman
+)
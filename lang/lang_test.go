@@ -0,0 +1,53 @@
+package lang
+
+import "testing"
+
+func TestLang(t *testing.T) {
+	data := []struct {
+		lang Lang
+		msg  string
+	}{
+		{LangEnglish, "English"},
+		{LangEN, "English"},
+		{LangDE, "German"},
+		{LangDeutsch, "German"},
+		{0, "Unknown"},
+	}
+
+	for _, d := range data {
+		t.Run(d.msg, func(t *testing.T) {
+			assertEqual(t, d.lang.String(), d.msg)
+		})
+	}
+}
+
+func TestParseLang(t *testing.T) {
+	data := map[string]Lang{
+		"English": LangEnglish,
+		"EN":      LangEnglish,
+		"German":  LangGerman,
+		"Deutsch": LangGerman,
+		"French":  LangFrench,
+	}
+
+	for msg, lang := range data {
+		t.Run(msg, func(t *testing.T) {
+			parsed, err := ParseLang(msg)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			assertEqual(t, parsed.String(), lang.String())
+
+			byName, ok := LangByName(msg)
+			if !ok || byName != lang {
+				t.Fatalf("Lang %q isn't found by name", msg)
+			}
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Lang message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,22 @@
+// Package level is used for testing purpose only
+package level
+
+//go:generate cmtstringer -type Level -yaml -xml -guard
+
+// Level type of contiguous logging level constant
+type Level int
+
+const (
+	// Trace Tracing details
+	Trace Level = iota - 1
+	// Debug Debugging information
+	Debug
+	// Info Informational message
+	Info
+	// Warning Warning condition
+	Warning
+	// Error Error condition
+	Error
+	// Warn Short name of Warning
+	Warn = Warning
+)
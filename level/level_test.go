@@ -0,0 +1,85 @@
+package level
+
+import (
+	"encoding/xml"
+	"fmt"
+	"testing"
+)
+
+func TestLevelMessage(t *testing.T) {
+	data := map[Level]string{
+		Trace:   "Tracing details",
+		Debug:   "Debugging information",
+		Info:    "Informational message",
+		Warning: "Warning condition",
+		Error:   "Error condition",
+	}
+
+	for level, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", level), msg)
+		})
+	}
+}
+
+func TestDuplicateLevelMessage(t *testing.T) {
+	// The comment of the constant declared first is used.
+	assertEqual(t, fmt.Sprintf("%v", Warn), "Warning condition")
+}
+
+func TestUnknownLevelMessage(t *testing.T) {
+	for _, level := range []Level{-2, 4, 100} {
+		assertEqual(t, fmt.Sprintf("%v", level), "Unknown")
+	}
+}
+
+func TestLevelYAML(t *testing.T) {
+	value, err := Warning.MarshalYAML()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertEqual(t, fmt.Sprintf("%v", value), "Warning condition")
+
+	if _, err := Level(100).MarshalYAML(); err == nil {
+		t.Fatal("Expected error for unknown value")
+	}
+
+	// The function stands for the one given by a YAML decoder.
+	var level Level
+	err = level.UnmarshalYAML(func(v interface{}) error {
+		*v.(*string) = "Debugging information"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertEqual(t, level.String(), Debug.String())
+}
+
+func TestLevelXML(t *testing.T) {
+	type entry struct {
+		Level Level `xml:"level"`
+	}
+
+	data, err := xml.Marshal(entry{Level: Info})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertEqual(t, string(data), "<entry><level>Informational message</level></entry>")
+
+	var e entry
+	if err := xml.Unmarshal([]byte("<entry><level>Error condition</level></entry>"), &e); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertEqual(t, e.Level.String(), Error.String())
+
+	if _, err := xml.Marshal(entry{Level: 100}); err == nil {
+		t.Fatal("Expected error for unknown value")
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Level message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// probeSource is the package the commands are run in.
+const probeSource = `package probe
+
+// Code type of constant
+type Code int
+
+const (
+	// CodeOK is fine
+	CodeOK Code = iota
+	// CodeBad is broken
+	CodeBad
+)
+
+// Dup type of constants having the same comment
+type Dup int
+
+const (
+	// DupA is the same
+	DupA Dup = iota
+	// DupB is the same
+	DupB
+)
+`
+
+// writeTestFiles writes the files given by their slash-separated names relative to the directory.
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		fileName := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fileName), 0775); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(fileName, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// setFlags sets the flags for the test, which are restored when it ends.
+func setFlags(t *testing.T, values map[string]string) {
+	for name, value := range values {
+		f := flag.Lookup(name)
+		if f == nil {
+			t.Fatalf("Unknown flag -%s", name)
+		}
+		previous := f.Value.String()
+		t.Cleanup(func() { f.Value.Set(previous) })
+		if err := f.Value.Set(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// captureLog returns the buffer the log is written to during the test.
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func TestArgTargets(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFiles(t, dir, map[string]string{
+		"a/a.go":            "package a",
+		"a/sub/sub.go":      "package sub",
+		"a/testdata/t.go":   "package t",
+		"a/vendor/v/v.go":   "package v",
+		"a/empty/README":    "",
+		"a/tests/x_test.go": "package tests",
+		"b/b.go":            "package b",
+		"b/sub/sub.go":      "package sub",
+	})
+
+	tests := []struct {
+		name      string
+		args      []string
+		recursive bool
+		expected  []target
+	}{
+		{"dirs", []string{"a", "b"}, false, []target{{"a", true, false}, {"b", true, false}}},
+		{"file", []string{"a/a.go"}, false, []target{{"a/a.go", false, false}}},
+		{"recursive", []string{"a", "b"}, true, []target{{"a", true, true}, {"a/sub", true, true},
+			{"b", true, true}, {"b/sub", true, true}}},
+		{"recursive file", []string{"a/a.go"}, true, []target{{"a/a.go", false, false}}},
+		{"dots first", []string{"a/...", "b"}, false, []target{{"a", true, true}, {"a/sub", true, true}, {"b", true, false}}},
+		{"dots last", []string{"b", "a/..."}, false, []target{{"b", true, false}, {"a", true, true}, {"a/sub", true, true}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			targets, err := argTargets(test.args, test.recursive)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			for i := range targets {
+				targets[i].path = filepath.ToSlash(targets[i].path)
+			}
+			if !reflect.DeepEqual(targets, test.expected) {
+				t.Fatalf("Targets are incorrect\nExpected: %v\nObtained: %v", test.expected, targets)
+			}
+		})
+	}
+
+	if _, err := argTargets([]string{"a/README"}, false); err == nil {
+		t.Fatal("Expected error for missing file")
+	}
+	writeTestFiles(t, dir, map[string]string{"a/README": ""})
+	if _, err := argTargets([]string{"a/README"}, false); err != errUsage {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestOutputPaths(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	writeTestFiles(t, dir, map[string]string{"gen/code.go": ""})
+
+	sep := string(filepath.Separator)
+	tests := []struct {
+		output, outputDir string
+		expected          [2]string
+	}{
+		{"", "", [2]string{"", ""}},
+		{"-", "", [2]string{"-", ""}},
+		{"code.go", "out", [2]string{"code.go", "out"}},
+		{"gen/code.go", "", [2]string{"gen/code.go", ""}},
+		{"gen", "", [2]string{"", "gen"}},
+		{"new" + sep, "", [2]string{"", "new" + sep}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.output, func(t *testing.T) {
+			output, outputDir, err := outputPaths(test.output, test.outputDir)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if [2]string{output, outputDir} != test.expected {
+				t.Fatalf("Output is incorrect\nExpected: %v\nObtained: %v", test.expected, [2]string{output, outputDir})
+			}
+		})
+	}
+
+	if _, _, err := outputPaths("gen", "out"); err == nil || err.Error() != "-output can't be a directory along with -outdir" {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestParsePerm(t *testing.T) {
+	data := map[string]os.FileMode{
+		"0644": 0644,
+		"600":  0600,
+		"0777": 0777,
+	}
+
+	for value, expected := range data {
+		t.Run(value, func(t *testing.T) {
+			mode, err := parsePerm(value)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if mode != expected {
+				t.Fatalf("Permission bits are incorrect\nExpected: %v\nObtained: %v", expected, mode)
+			}
+		})
+	}
+
+	for _, value := range []string{"644 ", "0888", "01000", "rw"} {
+		if _, err := parsePerm(value); err == nil {
+			t.Fatalf("Expected error for -perm %q", value)
+		}
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		flags    map[string]string
+		existing string
+		err      string
+		expected string
+	}{
+		{"new", nil, "", "", "// Code generated by cmtstringer. DO NOT EDIT.\n"},
+		{"generated", nil, "// Code generated by cmtstringer. DO NOT EDIT.\nold\n", "",
+			"// Code generated by cmtstringer. DO NOT EDIT.\n"},
+		{"hand-written", nil, "package probe\n", "isn't generated by cmtstringer, use -force to overwrite it",
+			"package probe\n"},
+		{"marker in string", nil, "package probe\n\nconst marker = \"// Code generated by cmtstringer. DO NOT EDIT.\"\n",
+			"isn't generated by cmtstringer, use -force to overwrite it",
+			"package probe\n\nconst marker = \"// Code generated by cmtstringer. DO NOT EDIT.\"\n"},
+		{"force", map[string]string{"force": "true"}, "package probe\n", "",
+			"// Code generated by cmtstringer. DO NOT EDIT.\n"},
+		{"dry-run", map[string]string{"dry-run": "true"}, "", "", ""},
+		{"dry-run hand-written", map[string]string{"dry-run": "true"}, "package probe\n",
+			"isn't generated by cmtstringer, use -force to overwrite it", "package probe\n"},
+	}
+
+	fileMode = 0644
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setFlags(t, test.flags)
+			captureLog(t)
+			fileName := filepath.Join(t.TempDir(), "code_string_gen.go")
+			if test.existing != "" {
+				if err := ioutil.WriteFile(fileName, []byte(test.existing), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := writeFile(fileName, []byte("// Code generated by cmtstringer. DO NOT EDIT.\n"))
+			if (test.err == "" && err != nil) || (test.err != "" && (err == nil || !strings.HasSuffix(err.Error(), test.err))) {
+				t.Fatalf("Error is incorrect\nExpected: %s\nObtained: %v", test.err, err)
+			}
+			content, err := ioutil.ReadFile(fileName)
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			if string(content) != test.expected {
+				t.Fatalf("File content is incorrect\nExpected: %s\nObtained: %s", test.expected, content)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags map[string]string
+		args  []string
+		err   error
+		log   string
+
+		// file is the generated file expected to contain the text, and have the mode unless 0.
+		file     string
+		contains string
+		mode     os.FileMode
+	}{
+		{"default", nil, nil, nil, "", "code_string_gen.go", `const _Code_name = "is fineis broken"`, 0},
+		{"outdir", map[string]string{"outdir": "out"}, nil, nil, "", "out/code_string_gen.go", "package probe\n", 0},
+		{"output dir", map[string]string{"output": "gen" + string(filepath.Separator)}, nil, nil, "",
+			"gen/code_string_gen.go", "package probe\n", 0},
+		{"output dir along with outdir", map[string]string{"output": "gen" + string(filepath.Separator), "outdir": "out"}, nil,
+			errors.New("-output can't be a directory along with -outdir"), "", "", "", 0},
+		{"output file of several dirs", map[string]string{"output": "out.go"}, []string{".", "sub"},
+			errors.New("-output out.go can't be used for 2 directories, use a directory or -outdir"), "", "", "", 0},
+		{"stdout of several dirs", map[string]string{"output": "-"}, []string{"./..."},
+			errors.New("-output - can't be used for 2 directories, use a directory or -outdir"), "", "", "", 0},
+		{"same outdir file of several dirs", map[string]string{"outdir": "gen"}, []string{".", "sub"}, errFailed,
+			"sub: gen/code_string_gen.go is generated for . already", "", "", 0},
+		{"perm", map[string]string{"perm": "0600"}, nil, nil, "", "code_string_gen.go", "", 0600},
+		{"invalid perm", map[string]string{"perm": "0999"}, nil,
+			errors.New(`invalid -perm "0999", must be octal permission bits, e.g. 0644`), "", "", "", 0},
+		{"header", map[string]string{"header": "Copyright The Authors"}, nil, nil, "",
+			"code_string_gen.go", "// Copyright The Authors\n\n// Code generated by cmtstringer. DO NOT EDIT.", 0},
+		{"package", map[string]string{"package": "codes"}, nil, nil, "", "code_string_gen.go", "package codes\n", 0},
+		{"invalid package", map[string]string{"package": "co-des"}, nil, errFailed, `invalid package name "co-des"`, "", "", 0},
+		{"strict", map[string]string{"type": "Dup", "strict": "true"}, nil, errFailed,
+			`constants of type Dup: DupA, DupB have the same comment "is the same"`, "", "", 0},
+		{"not strict", map[string]string{"type": "Dup"}, nil, nil, "", "dup_string_gen.go", `const _Dup_name = "is the sameis the same"`, 0},
+		{"dry-run", map[string]string{"dry-run": "true"}, nil, nil, "would write code_string_gen.go", "", "", 0},
+		{"undeclared", map[string]string{"type": "Cod"}, []string{"."}, errFailed, `no declared type "Cod" found`, "", "", 0},
+		{"undeclared recursive", map[string]string{"type": "Cod"}, []string{"./..."},
+			errors.New("no declared types Cod found in 2 directories"), "", "", "", 0},
+		{"recursive", map[string]string{"r": "true"}, []string{"."}, nil, "", "sub/code_string_gen.go", "package sub\n", 0},
+		{"recursive skips undeclared", map[string]string{"type": "Dup"}, []string{"./..."}, nil, "",
+			"dup_string_gen.go", "package probe\n", 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+			writeTestFiles(t, dir, map[string]string{
+				"go.mod":     "module probe\n\ngo 1.22\n",
+				"probe.go":   probeSource,
+				"sub/sub.go": "package sub\n\n// Code type of constant\ntype Code int\n\n// CodeSub is nested\nconst CodeSub Code = 1\n",
+			})
+			flags := map[string]string{"type": "Code", "q": "true"}
+			for name, value := range test.flags {
+				flags[name] = value
+			}
+			setFlags(t, flags)
+			logged := captureLog(t)
+
+			err := run(test.args)
+			if (test.err == nil && err != nil) || (test.err != nil && (err == nil || err.Error() != test.err.Error())) {
+				t.Fatalf("Error is incorrect\nExpected: %v\nObtained: %v", test.err, err)
+			}
+			if !strings.Contains(logged.String(), test.log) {
+				t.Fatalf("Log is incorrect\nExpected: %s\nObtained: %s", test.log, logged)
+			}
+
+			if test.file == "" {
+				if files, _ := filepath.Glob("*_gen.go"); len(files) > 0 {
+					t.Fatalf("Unexpected generated files: %v", files)
+				}
+				return
+			}
+			content, err := ioutil.ReadFile(filepath.FromSlash(test.file))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(content), test.contains) {
+				t.Fatalf("Generated file %s is incorrect\nExpected: %s\nObtained: %s", test.file, test.contains, content)
+			}
+			if test.mode != 0 {
+				info, err := os.Stat(filepath.FromSlash(test.file))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if info.Mode().Perm() != test.mode {
+					t.Fatalf("Mode of %s is incorrect\nExpected: %v\nObtained: %v", test.file, test.mode, info.Mode().Perm())
+				}
+			}
+		})
+	}
+}
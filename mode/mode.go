@@ -0,0 +1,16 @@
+// Package mode is used for testing purpose only
+package mode
+
+//go:generate cmtstringer -type Mode -ptr -json -text -assert
+
+// Mode type of constant with methods on pointer receivers
+type Mode int
+
+const (
+	// Read Read only
+	Read Mode = iota + 1
+	// Write Write only
+	Write
+	// ReadWrite Read and write
+	ReadWrite
+)
@@ -0,0 +1,56 @@
+package mode
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestModeMessage(t *testing.T) {
+	data := map[Mode]string{
+		Read:      "Read only",
+		Write:     "Write only",
+		ReadWrite: "Read and write",
+	}
+
+	for mode, msg := range data {
+		mode := mode
+		t.Run(msg, func(t *testing.T) {
+			// String is defined on the pointer receiver.
+			assertEqual(t, fmt.Sprintf("%v", &mode), msg)
+		})
+	}
+}
+
+func TestUnmarshalMode(t *testing.T) {
+	var mode Mode
+	if err := json.Unmarshal([]byte(`"Write only"`), &mode); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertEqual(t, mode.String(), "Write only")
+
+	if err := mode.UnmarshalText([]byte("Read and write")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertEqual(t, mode.String(), "Read and write")
+
+	if err := mode.UnmarshalText([]byte("Execute")); err == nil {
+		t.Fatal("Expected error for unknown message")
+	}
+	assertEqual(t, mode.String(), "Read and write")
+}
+
+func TestMarshalMode(t *testing.T) {
+	mode := Read
+	data, err := json.Marshal(&mode)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertEqual(t, string(data), `"Read only"`)
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Mode message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
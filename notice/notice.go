@@ -0,0 +1,18 @@
+// Package notice is used for testing purpose only
+package notice
+
+//go:generate cmtstringer -type Notice -strip-markup
+
+// Notice type of constant with markup in comments
+type Notice int
+
+const (
+	// NoticeMissing <b>Not Found</b>
+	NoticeMissing Notice = iota
+	// NoticeTerms Read the <a href="/terms">terms &amp; conditions</a>
+	NoticeTerms
+	// NoticeLimit Use &lt;b&gt; for bold, limit is 5 < 10
+	NoticeLimit
+	// NoticeBreak First line<br/>
+	NoticeBreak
+)
@@ -0,0 +1,24 @@
+package notice
+
+import "testing"
+
+func TestNotice(t *testing.T) {
+	data := map[Notice]string{
+		NoticeMissing: "Not Found",
+		NoticeTerms:   "Read the terms & conditions",
+		NoticeLimit:   "Use <b> for bold, limit is 5 < 10",
+		NoticeBreak:   "First line",
+	}
+
+	for notice, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, notice.String(), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Notice message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,19 @@
+// Package outcome is used for testing purpose only
+package outcome
+
+//go:generate cmtstringer -type Outcome -default %d
+
+// Outcome type of constant with a constant marked as the default case
+type Outcome int
+
+const (
+	// OutcomeUnknown Outcome is unknown
+	//cmtstringer:default
+	OutcomeUnknown Outcome = iota
+	// OutcomeWin Win
+	OutcomeWin
+	// OutcomeLoss Loss
+	OutcomeLoss
+	// OutcomeDraw Draw
+	OutcomeDraw
+)
@@ -0,0 +1,25 @@
+package outcome
+
+import "testing"
+
+func TestOutcome(t *testing.T) {
+	data := map[Outcome]string{
+		OutcomeUnknown: "Outcome is unknown",
+		OutcomeWin:     "Win",
+		OutcomeDraw:    "Draw",
+		-1:             "Outcome is unknown",
+		4:              "Outcome is unknown",
+	}
+
+	for outcome, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, outcome.String(), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Outcome message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,18 @@
+// Package perm is used for testing purpose only
+package perm
+
+//go:generate cmtstringer -type Perm -bitmask -bitmask-zero none
+
+// Perm type of bitmask constant
+type Perm uint
+
+const (
+	// PermRead read
+	PermRead Perm = 1 << iota
+	// PermWrite write
+	PermWrite
+	// PermExec exec
+	PermExec
+	// PermReadWrite read-write
+	PermReadWrite = PermRead | PermWrite
+)
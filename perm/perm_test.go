@@ -0,0 +1,29 @@
+package perm
+
+import "testing"
+
+func TestPerm(t *testing.T) {
+	data := map[Perm]string{
+		0:                        "none",
+		PermRead:                 "read",
+		PermExec:                 "exec",
+		PermReadWrite:            "read-write",
+		PermRead | PermExec:      "read|exec",
+		PermWrite | PermExec:     "write|exec",
+		PermReadWrite | PermExec: "read|write|exec",
+		PermWrite | 1<<5:         "Unknown",
+		1 << 5:                   "Unknown",
+	}
+
+	for perm, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, perm.String(), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Perm message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
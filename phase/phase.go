@@ -0,0 +1,18 @@
+// Package phase is used for testing purpose only
+package phase
+
+//go:generate cmtstringer -type phase -include-unexported
+
+// phase type of unexported constant
+type phase int
+
+const (
+	// phaseNew Just created
+	phaseNew phase = iota
+	// phaseRunning In progress
+	phaseRunning
+	// phaseDone Finished
+	phaseDone
+	// PhaseFailed Failed, the only exported one
+	PhaseFailed
+)
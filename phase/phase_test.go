@@ -0,0 +1,28 @@
+package phase
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPhaseMessage(t *testing.T) {
+	data := map[phase]string{
+		phaseNew:     "Just created",
+		phaseRunning: "In progress",
+		phaseDone:    "Finished",
+		PhaseFailed:  "Failed, the only exported one",
+		4:            "Unknown",
+	}
+
+	for p, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", p), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("phase message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
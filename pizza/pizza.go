@@ -0,0 +1,37 @@
+// Package pizza is used for testing purpose only
+package pizza
+
+//go:generate cmtstringer -all
+
+// Size type of constant found with -all
+type Size int
+
+const (
+	// SizeSmall Small, 25 cm
+	SizeSmall Size = iota + 1
+	// SizeMedium Medium, 30 cm
+	SizeMedium
+	// SizeLarge Large, 35 cm
+	SizeLarge
+)
+
+// Topping type of string constant found with -all
+type Topping string
+
+const (
+	// ToppingCheese Extra cheese
+	ToppingCheese Topping = "cheese"
+	// ToppingMushroom Mushrooms
+	ToppingMushroom Topping = "mushroom"
+)
+
+// Slices type of constant without comments, which is skipped
+type Slices int
+
+const (
+	SlicesFew  Slices = 4
+	SlicesMany Slices = 8
+)
+
+// Oven type of constant with a non-basic underlying type, which is skipped
+type Oven struct{}
@@ -0,0 +1,38 @@
+package pizza
+
+import "testing"
+
+func TestSize(t *testing.T) {
+	data := map[Size]string{
+		SizeSmall:  "Small, 25 cm",
+		SizeMedium: "Medium, 30 cm",
+		SizeLarge:  "Large, 35 cm",
+		0:          "Unknown",
+	}
+
+	for size, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, size.String(), msg)
+		})
+	}
+}
+
+func TestTopping(t *testing.T) {
+	data := map[Topping]string{
+		ToppingCheese:   "Extra cheese",
+		ToppingMushroom: "Mushrooms",
+		"pineapple":     "Unknown",
+	}
+
+	for topping, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, topping.String(), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Pizza message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,20 @@
+// Package planet is used for testing purpose only
+package planet
+
+//go:generate cmtstringer -type Planet -trimprefix Planet -name-fallback -match ^Planet
+
+// Planet type of partially documented constant
+type Planet int
+
+const (
+	PlanetMercury Planet = iota + 1
+	PlanetVenus
+	// PlanetEarth Home planet
+	PlanetEarth
+	PlanetMars
+	// Not a comment with the constant name
+	PlanetJupiter
+)
+
+// NumPlanets Number of planets, it isn't a planet itself
+const NumPlanets Planet = 5
@@ -0,0 +1,38 @@
+package planet
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPlanetMessage(t *testing.T) {
+	data := map[Planet]string{
+		PlanetMercury: "Mercury",
+		PlanetVenus:   "Venus",
+		PlanetEarth:   "Home planet",
+		PlanetMars:    "Mars",
+		PlanetJupiter: "Not a comment with the constant name",
+	}
+
+	for planet, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", planet), msg)
+		})
+	}
+}
+
+func TestUnmatchedPlanetMessage(t *testing.T) {
+	assertEqual(t, fmt.Sprintf("%v", NumPlanets), "Not a comment with the constant name")
+}
+
+func TestUnknownPlanetMessage(t *testing.T) {
+	for _, planet := range []Planet{0, 6} {
+		assertEqual(t, fmt.Sprintf("%v", planet), "Unknown")
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Planet message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
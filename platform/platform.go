@@ -0,0 +1,14 @@
+// Package platform is used for testing purpose only
+package platform
+
+//go:generate cmtstringer -type Platform -tags alpha,beta -per-tag -compat go1.16
+
+// Platform type of constant declared across build-tagged files
+type Platform int
+
+const (
+	// PlatformGeneric Generic platform
+	PlatformGeneric Platform = iota
+	// PlatformEmbedded Embedded platform
+	PlatformEmbedded
+)
@@ -0,0 +1,10 @@
+//go:build alpha
+
+package platform
+
+const (
+	// PlatformAlpha Alpha platform
+	PlatformAlpha Platform = iota + 10
+	// PlatformAlphaServer Alpha server platform
+	PlatformAlphaServer
+)
@@ -0,0 +1,19 @@
+//go:build alpha
+
+package platform
+
+import "testing"
+
+func TestPlatformAlpha(t *testing.T) {
+	data := map[Platform]string{
+		PlatformAlpha:       "Alpha platform",
+		PlatformAlphaServer: "Alpha server platform",
+		20:                  "Unknown",
+	}
+
+	for platform, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, platform.String(), msg)
+		})
+	}
+}
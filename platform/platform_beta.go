@@ -0,0 +1,6 @@
+//go:build beta
+
+package platform
+
+// PlatformBeta Beta platform
+const PlatformBeta Platform = 20
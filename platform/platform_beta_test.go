@@ -0,0 +1,18 @@
+//go:build beta
+
+package platform
+
+import "testing"
+
+func TestPlatformBeta(t *testing.T) {
+	data := map[Platform]string{
+		PlatformBeta: "Beta platform",
+		10:           "Unknown",
+	}
+
+	for platform, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, platform.String(), msg)
+		})
+	}
+}
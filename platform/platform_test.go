@@ -0,0 +1,22 @@
+package platform
+
+import "testing"
+
+func TestPlatform(t *testing.T) {
+	data := map[Platform]string{
+		PlatformGeneric:  "Generic platform",
+		PlatformEmbedded: "Embedded platform",
+	}
+
+	for platform, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, platform.String(), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Platform message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,18 @@
+// Package priority is used for testing purpose only
+package priority
+
+//go:generate cmtstringer -type Priority -append -int
+
+// Priority type of uint8-based priority constant
+type Priority uint8
+
+const (
+	// Low Low priority
+	Low Priority = 252 + iota
+	// Normal Normal priority
+	Normal
+	// High High priority
+	High
+	// Highest Highest priority
+	Highest
+)
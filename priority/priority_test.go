@@ -0,0 +1,48 @@
+package priority
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPriorityMessage(t *testing.T) {
+	data := map[Priority]string{
+		Low:     "Low priority",
+		Normal:  "Normal priority",
+		High:    "High priority",
+		Highest: "Highest priority",
+	}
+
+	for priority, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", priority), msg)
+		})
+	}
+}
+
+func TestUnknownPriorityMessage(t *testing.T) {
+	for _, priority := range []Priority{0, 1, 3, 4, 251} {
+		assertEqual(t, fmt.Sprintf("%v", priority), "Unknown")
+	}
+}
+
+func TestAppendPriority(t *testing.T) {
+	b := []byte("priority: ")
+	assertEqual(t, string(High.Append(b)), "priority: High priority")
+	assertEqual(t, string(Priority(0).Append(b)), "priority: Unknown")
+
+	if allocs := testing.AllocsPerRun(100, func() { b = Highest.Append(b[:0]) }); allocs != 0 {
+		t.Fatalf("Append allocates %v times", allocs)
+	}
+}
+
+func TestPriorityInt(t *testing.T) {
+	var value uint8 = Highest.Int()
+	assertEqual(t, fmt.Sprintf("%d", value), "255")
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Priority message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
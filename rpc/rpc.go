@@ -0,0 +1,16 @@
+// Package rpc is used for testing purpose only
+package rpc
+
+//go:generate cmtstringer -type Code -msg-prefix RPC: -parse -values
+
+// Code type of constant having messages prefixed by -msg-prefix
+type Code int
+
+const (
+	// CodeCanceled Canceled
+	CodeCanceled Code = iota + 1
+	// CodeNotFound Not Found
+	CodeNotFound
+	// CodeUnavailable Service Unavailable
+	CodeUnavailable
+)
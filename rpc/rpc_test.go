@@ -0,0 +1,44 @@
+package rpc
+
+import "testing"
+
+func TestCode(t *testing.T) {
+	data := map[Code]string{
+		CodeCanceled:    "RPC: Canceled",
+		CodeNotFound:    "RPC: Not Found",
+		CodeUnavailable: "RPC: Service Unavailable",
+		0:               "Unknown",
+	}
+
+	for code, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, code.String(), msg)
+		})
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	code, err := ParseCode("RPC: Not Found")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	assertEqual(t, code.String(), CodeNotFound.String())
+
+	if _, err := ParseCode("Not Found"); err == nil {
+		t.Fatal("Expected error for message without prefix")
+	}
+}
+
+func TestCodeStrings(t *testing.T) {
+	strs := CodeStrings()
+	if len(strs) != 3 {
+		t.Fatalf("Unexpected number of strings: %d", len(strs))
+	}
+	assertEqual(t, strs[2], "RPC: Service Unavailable")
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Code message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
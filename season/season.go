@@ -0,0 +1,22 @@
+// Package season is used for testing purpose only
+package season
+
+//go:generate cmtstringer -type Season -multiline
+
+// Season type of season constant documented by multi-line comments
+type Season int
+
+const (
+	// Spring Spring
+	// from March to May
+	Spring Season = 3
+	// Summer
+	// Summer
+	// from June to August
+	Summer Season = 6
+	// Autumn "Autumn"
+	//	from September to November
+	Autumn Season = 9
+	// Winter Winter
+	Winter Season = 12
+)
@@ -0,0 +1,27 @@
+package season
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSeasonMessage(t *testing.T) {
+	data := map[Season]string{
+		Spring: "Spring\nfrom March to May",
+		Summer: "Summer\nfrom June to August",
+		Autumn: "\"Autumn\"\n\tfrom September to November",
+		Winter: "Winter",
+	}
+
+	for season, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", season), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Season message is incorrect\nExpected: %q\nObtained: %q", expected, actual)
+	}
+}
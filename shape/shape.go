@@ -0,0 +1,24 @@
+// Package shape is used for testing purpose only
+package shape
+
+//go:generate cmtstringer -type Figure -no-default numeric
+
+// Shape type of geometric shape constant
+type Shape int
+
+// Figure alias of type Shape, methods are generated for Shape
+type Figure = Shape
+
+const (
+	// Circle Round figure
+	Circle Figure = iota + 1
+	// Triangle Three-sided figure
+	Triangle
+	// Round Another name of Circle
+	Round = Circle
+)
+
+const (
+	// Square Four-sided shape
+	Square Shape = 4
+)
@@ -0,0 +1,37 @@
+package shape
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestShapeMessage(t *testing.T) {
+	data := map[Shape]string{
+		Circle:   "Round figure",
+		Triangle: "Three-sided figure",
+		Square:   "Four-sided shape",
+	}
+
+	for shape, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", shape), msg)
+		})
+	}
+}
+
+func TestDuplicateShapeMessage(t *testing.T) {
+	// The comment of the constant declared first is used.
+	assertEqual(t, fmt.Sprintf("%v", Round), "Round figure")
+}
+
+func TestUnknownShapeMessage(t *testing.T) {
+	for _, shape := range []Shape{0, 3, 5} {
+		assertEqual(t, fmt.Sprintf("%v", shape), fmt.Sprintf("%d", shape))
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Shape message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,22 @@
+// Package stage is used for testing purpose only
+package stage
+
+//go:generate cmtstringer -type Stage
+
+// Stage type of constant with skipped constants
+type Stage int
+
+const (
+	// StageDraft Draft
+	StageDraft Stage = iota
+	// StageReview In review
+	StageReview
+	// StagePublished Published
+	StagePublished
+
+	// StageLegacy Legacy stage
+	//cmtstringer:skip kept for compatibility only
+	StageLegacy Stage = 100
+	// StageFinal Final
+	StageFinal = StagePublished //cmtstringer:skip
+)
@@ -0,0 +1,31 @@
+package stage
+
+import "testing"
+
+func TestStage(t *testing.T) {
+	data := map[Stage]string{
+		StageDraft:     "Draft",
+		StageReview:    "In review",
+		StagePublished: "Published",
+		StageLegacy:    "Unknown",
+	}
+
+	for stage, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, stage.String(), msg)
+		})
+	}
+}
+
+func TestStagePacked(t *testing.T) {
+	// The skipped constant doesn't break the contiguous values packed into an array.
+	if len(_Stage_index) != 4 {
+		t.Fatalf("Stage comments are packed incorrectly: %v", _Stage_index)
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Stage message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
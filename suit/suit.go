@@ -0,0 +1,14 @@
+// Package suit is used for testing purpose only
+package suit
+
+//go:generate cmtstringer -type Rank,Suit -values -default panic
+
+// Suit type of playing card suit constant declared in several files
+type Suit int
+
+const (
+	// Clubs Clubs of black color
+	Clubs Suit = 1
+	// Spades Spades of black color
+	Spades Suit = 4
+)
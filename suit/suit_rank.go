@@ -0,0 +1,13 @@
+package suit
+
+// Rank type of playing card rank constant generated along with Suit
+type Rank int
+
+const (
+	// Jack Jack of the suit
+	Jack Rank = 11
+	// Queen Queen of the suit
+	Queen Rank = 12
+	// King King of the suit
+	King Rank = 13
+)
@@ -0,0 +1,8 @@
+package suit
+
+const (
+	// Diamonds Diamonds of red color
+	Diamonds Suit = 2
+	// Hearts Hearts of red color
+	Hearts Suit = 3
+)
@@ -0,0 +1,79 @@
+package suit
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestSuitMessage(t *testing.T) {
+	data := map[Suit]string{
+		Clubs:    "Clubs of black color",
+		Diamonds: "Diamonds of red color",
+		Hearts:   "Hearts of red color",
+		Spades:   "Spades of black color",
+	}
+
+	for suit, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", suit), msg)
+		})
+	}
+}
+
+func TestSuitValues(t *testing.T) {
+	// Constants are listed file by file, in the order of file names.
+	assertEqual(t, fmt.Sprintf("%v", SuitValues()), fmt.Sprintf("%v", []Suit{Clubs, Spades, Diamonds, Hearts}))
+}
+
+func TestRankMessage(t *testing.T) {
+	data := map[Rank]string{
+		Jack:  "Jack of the suit",
+		Queen: "Queen of the suit",
+		King:  "King of the suit",
+	}
+
+	for rank, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", rank), msg)
+		})
+	}
+}
+
+func TestUnknownSuitPanics(t *testing.T) {
+	defer func() {
+		err, ok := recover().(error)
+		if !ok {
+			t.Fatal("Expected panic with error for unknown value")
+		}
+		assertEqual(t, err.Error(), "invalid Suit: 5")
+	}()
+	_ = Suit(5).String()
+}
+
+func TestCombinedFile(t *testing.T) {
+	data, err := ioutil.ReadFile("suit_string_gen.go")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	src := string(data)
+
+	// Types are generated in the order they are listed, not declared.
+	if !strings.Contains(src, "// Types: Rank, Suit\n") {
+		t.Fatal("Header comment doesn't mention all types")
+	}
+	rank, suit := strings.Index(src, "func (r Rank) String()"), strings.Index(src, "func (s Suit) String()")
+	if rank < 0 || suit < 0 || rank > suit {
+		t.Fatal("Methods of types are out of order")
+	}
+	if strings.Count("\n"+src, "\npackage ") != 1 {
+		t.Fatal("Package clause isn't single")
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Suit message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,20 @@
+// Package tier is used for testing purpose only
+package tier
+
+//go:generate cmtstringer -type Tier,Status -auto-trimprefix
+
+// Tier type of partially documented constant with a common name prefix
+type Tier int
+
+const (
+	TierFree Tier = iota
+	TierBasic
+	// TierPremium Premium with support
+	TierPremium
+	TierEnterprise
+)
+
+// Status type of a single undocumented constant, which has no common name prefix
+type Status int
+
+const StatusOK Status = 1
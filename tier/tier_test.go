@@ -0,0 +1,31 @@
+package tier
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTierMessage(t *testing.T) {
+	data := map[Tier]string{
+		TierFree:       "Free",
+		TierBasic:      "Basic",
+		TierPremium:    "Premium with support",
+		TierEnterprise: "Enterprise",
+	}
+
+	for tier, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", tier), msg)
+		})
+	}
+}
+
+func TestStatusMessage(t *testing.T) {
+	assertEqual(t, fmt.Sprintf("%v", StatusOK), "StatusOK")
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Tier message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,14 @@
+// Package toggle is used for testing purpose only
+package toggle
+
+//go:generate cmtstringer -type Toggle -sort value -values
+
+// Toggle type of bool-based constant
+type Toggle bool
+
+const (
+	// On Switched on
+	On Toggle = true
+	// Off Switched off
+	Off Toggle = false
+)
@@ -0,0 +1,32 @@
+package toggle
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToggleMessage(t *testing.T) {
+	data := map[Toggle]string{
+		On:  "Switched on",
+		Off: "Switched off",
+	}
+
+	for toggle, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", toggle), msg)
+		})
+	}
+}
+
+func TestToggleValues(t *testing.T) {
+	values := ToggleValues()
+	if len(values) != 2 || values[0] != Off || values[1] != On {
+		t.Fatalf("Toggle values are incorrect: %v", values)
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Toggle message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
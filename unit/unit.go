@@ -0,0 +1,20 @@
+// Package unit is used for testing purpose only
+package unit
+
+//go:generate cmtstringer -type Unit -template unit.tmpl
+
+// Unit type of measurement unit constant
+type Unit int
+
+// u is the prefix of unit messages, the receiver of generated methods must not
+// shadow it.
+const u = "unit: "
+
+const (
+	// Meter Meter
+	Meter Unit = iota + 1
+	// Gram Gram
+	Gram
+	// Second Second
+	Second
+)
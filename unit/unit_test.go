@@ -0,0 +1,27 @@
+package unit
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestUnitMessage(t *testing.T) {
+	data := map[Unit]string{
+		Meter:  "unit: Meter",
+		Gram:   "unit: Gram",
+		Second: "unit: Second",
+		0:      "unit: Unknown",
+	}
+
+	for unit, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", unit), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Unit message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
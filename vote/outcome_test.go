@@ -0,0 +1,31 @@
+package vote_test
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Outcome type of vote outcome constant declared in external test package
+type Outcome int
+
+const (
+	// Passed Motion passed
+	Passed Outcome = iota + 1
+	// Failed Motion failed
+	Failed
+)
+
+func TestOutcomeMessage(t *testing.T) {
+	data := map[Outcome]string{
+		Passed: "Motion passed",
+		Failed: "Motion failed",
+	}
+
+	for outcome, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			if actual := fmt.Sprintf("%v", outcome); actual != msg {
+				t.Fatalf("Outcome message is incorrect\nExpected: %s\nObtained: %s", msg, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+// Package vote is used for testing purpose only
+package vote
+
+//go:generate cmtstringer -type Ballot,Outcome -include-tests
+
+// Ballot type of vote constant
+type Ballot int
+
+const (
+	// Yes In favor
+	Yes Ballot = iota + 1
+	// No Against
+	No
+)
@@ -0,0 +1,25 @@
+package vote
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBallotMessage(t *testing.T) {
+	data := map[Ballot]string{
+		Yes: "In favor",
+		No:  "Against",
+	}
+
+	for ballot, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", ballot), msg)
+		})
+	}
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Ballot message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
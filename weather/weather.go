@@ -0,0 +1,16 @@
+// Package weather is used for testing purpose only
+package weather
+
+//go:generate cmtstringer -type Weather -messages de=de.csv -messages fr=fr.csv
+
+// Weather type of constant having messages in several languages
+type Weather int
+
+const (
+	// WeatherSunny Sunny
+	WeatherSunny Weather = iota + 1
+	// WeatherRainy Rainy
+	WeatherRainy
+	// WeatherSnowy Snowy
+	WeatherSnowy
+)
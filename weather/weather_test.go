@@ -0,0 +1,34 @@
+package weather
+
+import "testing"
+
+func TestWeatherStringIn(t *testing.T) {
+	data := []struct {
+		weather Weather
+		lang    string
+		msg     string
+	}{
+		{WeatherSunny, "de", "Sonnig"},
+		{WeatherSnowy, "de", "Verschneit"},
+		{WeatherRainy, "fr", "Pluvieux"},
+		{WeatherSnowy, "fr", "Snowy"},
+		{WeatherRainy, "es", "Rainy"},
+		{0, "de", "Unknown"},
+	}
+
+	for _, d := range data {
+		t.Run(d.lang+" "+d.msg, func(t *testing.T) {
+			assertEqual(t, d.weather.StringIn(d.lang), d.msg)
+		})
+	}
+}
+
+func TestWeather(t *testing.T) {
+	assertEqual(t, WeatherSunny.String(), "Sunny")
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Weather message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}
@@ -0,0 +1,20 @@
+// Package weekday is used for testing purpose only
+package weekday
+
+//go:generate cmtstringer -type Weekday -validate -guard
+
+// Weekday type of iota-based day of week constant
+type Weekday int
+
+const (
+	// Sunday First day of week
+	Sunday Weekday = iota
+	// Monday Second day of week
+	Monday
+	// Tuesday Third day of week
+	Tuesday
+	// _ Wednesday is a placeholder, yet it advances iota
+	_
+	// Thursday Fifth day of week
+	Thursday
+)
@@ -0,0 +1,52 @@
+package weekday
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWeekdayMessage(t *testing.T) {
+	data := map[Weekday]string{
+		Sunday:   "First day of week",
+		Monday:   "Second day of week",
+		Tuesday:  "Third day of week",
+		Thursday: "Fifth day of week",
+	}
+
+	for day, msg := range data {
+		t.Run(msg, func(t *testing.T) {
+			assertEqual(t, fmt.Sprintf("%v", day), msg)
+		})
+	}
+}
+
+func TestSkippedWeekdayMessage(t *testing.T) {
+	assertEqual(t, fmt.Sprintf("%v", Weekday(3)), "Unknown")
+}
+
+func TestWeekdayAfterPlaceholder(t *testing.T) {
+	assertEqual(t, fmt.Sprintf("%v", Weekday(4)), "Fifth day of week")
+	if err := Weekday(4).Validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestValidateWeekday(t *testing.T) {
+	for _, day := range []Weekday{Sunday, Monday, Tuesday, Thursday} {
+		if err := day.Validate(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	err := Weekday(3).Validate()
+	if err == nil {
+		t.Fatal("Expected error for skipped value")
+	}
+	assertEqual(t, err.Error(), "invalid Weekday: 3")
+}
+
+func assertEqual(t *testing.T, actual, expected string) {
+	if actual != expected {
+		t.Fatalf("Weekday message is incorrect\nExpected: %s\nObtained: %s", expected, actual)
+	}
+}